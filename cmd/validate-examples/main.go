@@ -0,0 +1,348 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command validate-examples decodes and validates Kubernetes manifests the
+// way the tests of the website examples do, outside of go test.
+//
+// Usage:
+//
+//	validate-examples [flags] [file|directory|pattern|-]...
+//
+// Arguments name YAML or JSON files, directories walked for the files they
+// hold, glob patterns matching either, or - for a manifest read from the
+// standard input. Files under content/<locale>/examples get the expectations
+// of the examples.yaml manifests of the website. Elsewhere, a file named
+// examples.yaml is checked like any other. The command exits with status 1
+// when a file fails and 2 when it cannot check them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/website/pkg/examples"
+	"k8s.io/website/pkg/examples/report"
+)
+
+// stdinPath is the path of the file read from the standard input in the
+// results. It is checked as a file of the current directory.
+const stdinPath = "<stdin>"
+
+// options are the flags of the command.
+type options struct {
+	kubernetesVersion string
+	failOnWarnings    bool
+	failOnReferences  bool
+	roundTrip         bool
+	reportPath        string
+	reportFormat      string
+	quiet             bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses the flags and arguments of the command, checks the files they
+// name, printing their errors and warnings to stdout, and returns the exit
+// status of the command.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var o options
+	flags := flag.NewFlagSet("validate-examples", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&o.kubernetesVersion, "kubernetes-version", "", "Kubernetes release to validate against, defaults to the release matching k8s.io/apimachinery")
+	flags.BoolVar(&o.failOnWarnings, "fail-on-warnings", false, "Fail the files raising warnings their manifest does not expect")
+	flags.BoolVar(&o.failOnReferences, "fail-on-dangling-references", false, "Fail the files referring to objects that are neither among the files of their directory nor declared as externals")
+	flags.BoolVar(&o.roundTrip, "round-trip", false, "Convert the objects to every served version of their kind and back, warning about lossy conversions")
+	flags.StringVar(&o.reportPath, "report", "", "Write a report of the outcome of checking every file to this file")
+	flags.StringVar(&o.reportFormat, "report-format", string(report.JSON), "Format of the -report file: json, junit or sarif")
+	flags.BoolVar(&o.quiet, "q", false, "Only print errors")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s [flags] [file|directory|pattern|-]...\n\n", flags.Name())
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	failed, err := check(flags.Args(), o, stdin, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// check checks the files args name, printing their errors and warnings to
+// out, and reports whether any failed.
+func check(args []string, o options, stdin io.Reader, out io.Writer) (bool, error) {
+	kubeVersion, err := examples.TargetVersion(o.kubernetesVersion)
+	if err != nil {
+		return false, fmt.Errorf("%v, use -kubernetes-version", err)
+	}
+	format := report.Format(o.reportFormat)
+	if !slices.Contains(report.Formats, format) {
+		return false, fmt.Errorf("unknown -report-format %q, expected one of %v", format, report.Formats)
+	}
+	paths, readStdin, err := expand(args)
+	if err != nil {
+		return false, err
+	}
+
+	// Files are checked along with those of the same examples directory,
+	// whose manifests they share.
+	var roots []string
+	pathsByRoot := map[string][]string{}
+	for _, path := range paths {
+		root := examplesRoot(path)
+		if _, ok := pathsByRoot[root]; !ok {
+			roots = append(roots, root)
+		}
+		pathsByRoot[root] = append(pathsByRoot[root], path)
+	}
+	if readStdin {
+		if _, ok := pathsByRoot["."]; !ok {
+			roots = append(roots, ".")
+			pathsByRoot["."] = nil
+		}
+	}
+
+	var runs []report.Run
+	var checked, failed, skipped int
+	for _, root := range roots {
+		checker := examples.NewChecker(root, kubeVersion)
+		checker.FailOnWarnings = o.failOnWarnings
+		checker.FailOnDanglingReferences = o.failOnReferences
+		checker.RoundTrip = o.roundTrip
+		// only the examples of the website have manifests
+		checker.IgnoreManifests = !examples.IsExamplesDir(root)
+
+		var results []examples.FileResult
+		checker.CheckFiles(pathsByRoot[root], func(r examples.FileResult) {
+			results = append(results, r)
+		})
+		if readStdin && root == "." {
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return false, fmt.Errorf("unable to read the standard input: %v", err)
+			}
+			stdinResult := []examples.FileResult{checker.CheckData(stdinPath, data)}
+			checker.ResolveReferences(stdinResult)
+			results = append(results, stdinResult...)
+		}
+
+		for _, r := range results {
+			checked++
+			switch {
+			case r.Skipped != "":
+				skipped++
+			case r.Failed():
+				failed++
+			}
+			printResult(out, r, o.quiet)
+		}
+		runs = append(runs, examples.ReportRun(root, kubeVersion, results))
+	}
+
+	if o.reportPath != "" {
+		var buf bytes.Buffer
+		if err := (&report.Report{Runs: runs}).Write(&buf, format); err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(o.reportPath, buf.Bytes(), 0644); err != nil {
+			return false, fmt.Errorf("unable to write the report: %v", err)
+		}
+	}
+	if !o.quiet {
+		fmt.Fprintf(out, "Checked %d files against Kubernetes %s: %d failed, %d skipped\n", checked, kubeVersion, failed, skipped)
+	}
+	return failed > 0, nil
+}
+
+// expand returns the files args name, in order: the files themselves, the
+// example files of the directories and the matches of the glob patterns. It
+// reports whether the standard input is to be read as well.
+func expand(args []string) (paths []string, readStdin bool, err error) {
+	seen := map[string]bool{}
+	add := func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+			return nil
+		}
+		return walk(path, func(path string) error {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+			return nil
+		})
+	}
+
+	for _, arg := range args {
+		if arg == "-" {
+			readStdin = true
+			continue
+		}
+		if !strings.ContainsAny(arg, "*?[") {
+			if err := add(filepath.Clean(arg)); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid pattern %q: %v", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, false, fmt.Errorf("no file matches %q", arg)
+		}
+		for _, match := range matches {
+			if err := add(match); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return paths, readStdin, nil
+}
+
+// examplesRoot returns the examples directory of a locale the file at path
+// belongs to, content/<locale>/examples, relative to the current directory
+// when path is. Files outside the examples of the website are their own
+// root.
+func examplesRoot(path string) string {
+	dir := filepath.Dir(path)
+	root, ok := localeExamplesDir(dir)
+	if !ok {
+		return dir
+	}
+	if filepath.IsAbs(path) {
+		return root
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, root); err == nil {
+			return rel
+		}
+	}
+	return root
+}
+
+// localeExamplesDir returns the absolute path of the examples directory of a
+// locale dir is, or is under, and reports whether there is one.
+func localeExamplesDir(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if examples.IsExamplesDir(d) {
+			return d, true
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return "", false
+		}
+	}
+}
+
+// walk calls fn with the path of every json/yaml file under dir. Under the
+// examples directory of a locale, manifests and golden files are left out,
+// see examples.WalkConfigFiles. Elsewhere, files named like them are checked
+// like any other.
+func walk(dir string, fn func(path string) error) error {
+	if _, ok := localeExamplesDir(dir); ok {
+		return examples.WalkConfigFiles(dir, fn)
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if examples.IsExamplesDir(path) {
+				if err := examples.WalkConfigFiles(path, fn); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".json" || ext == ".yaml" {
+			return fn(path)
+		}
+		return nil
+	})
+}
+
+// printResult prints the errors of a checked file and, unless quiet, its
+// unexpected warnings, with their position when known.
+func printResult(w io.Writer, r examples.FileResult, quiet bool) {
+	if r.Skipped != "" {
+		if !quiet {
+			fmt.Fprintf(w, "%s: skipped: %s\n", r.Path, r.Skipped)
+		}
+		return
+	}
+	for _, err := range r.Errors {
+		fmt.Fprintf(w, "%s: %v\n", r.Path, err)
+	}
+	for _, doc := range r.Documents {
+		for _, err := range doc.Errors {
+			position := r.Path
+			if location, ok := examples.ErrorLocation(err); ok {
+				position = location.String()
+			}
+			fmt.Fprintf(w, "%s: document %d (%s): %v\n", position, doc.Index, doc.Kind.Kind, err)
+		}
+		for _, err := range doc.Rejections {
+			fmt.Fprintf(w, "%s: document %d (%s): rejected: %v\n", r.Path, doc.Index, doc.Kind.Kind, err)
+		}
+		if quiet {
+			continue
+		}
+		for _, warning := range doc.Warnings {
+			if warning.Expected {
+				continue
+			}
+			position := r.Path
+			if warning.Location != nil {
+				position = warning.Location.String()
+			}
+			fmt.Fprintf(w, "%s: document %d (%s): warning: %s\n", position, doc.Index, doc.Kind.Kind, warning)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+
+const invalidPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers: []
+`
+
+const skipManifest = `skip: "not an example"
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid", "configmap.yaml")
+	invalid := filepath.Join(dir, "invalid", "pod.yaml")
+	// examples.yaml is only a manifest under content/<locale>/examples
+	other := filepath.Join(t.TempDir(), "other", "examples.yaml")
+	website := t.TempDir()
+	skipped := filepath.Join(website, "content", "en", "examples", "skipped")
+	for path, content := range map[string]string{
+		valid:                                   validConfigMap,
+		invalid:                                 invalidPod,
+		other:                                   invalidPod,
+		filepath.Join(skipped, "pod.yaml"):      invalidPod,
+		filepath.Join(skipped, "examples.yaml"): skipManifest,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantStatus int
+		wantOutput []string
+	}{{
+		name:       "valid file",
+		args:       []string{valid},
+		wantOutput: []string{"Checked 1 files", "0 failed"},
+	}, {
+		name:       "invalid file",
+		args:       []string{invalid},
+		wantStatus: 1,
+		wantOutput: []string{invalid + ":", "spec.containers", "1 failed"},
+	}, {
+		name:       "directory",
+		args:       []string{dir},
+		wantStatus: 1,
+		wantOutput: []string{"Checked 2 files", "1 failed"},
+	}, {
+		name:       "file named like a manifest",
+		args:       []string{other},
+		wantStatus: 1,
+		wantOutput: []string{other + ":", "spec.containers", "Checked 1 files"},
+	}, {
+		name:       "directory holding a file named like a manifest",
+		args:       []string{filepath.Dir(other)},
+		wantStatus: 1,
+		wantOutput: []string{other + ":", "Checked 1 files", "1 failed"},
+	}, {
+		name:       "examples with a manifest",
+		args:       []string{skipped},
+		wantOutput: []string{"Checked 1 files", "0 failed, 1 skipped"},
+	}, {
+		name:       "valid stdin",
+		args:       []string{"-"},
+		stdin:      validConfigMap,
+		wantOutput: []string{"Checked 1 files", "0 failed"},
+	}, {
+		name:       "invalid stdin",
+		args:       []string{"-q", "-"},
+		stdin:      invalidPod,
+		wantStatus: 1,
+		wantOutput: []string{stdinPath + ":", "spec.containers"},
+	}, {
+		name:       "no argument",
+		wantStatus: 2,
+	}, {
+		name:       "missing file",
+		args:       []string{filepath.Join(dir, "missing.yaml")},
+		wantStatus: 2,
+	}, {
+		name:       "unknown report format",
+		args:       []string{"-report-format=html", valid},
+		wantStatus: 2,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			if status != tc.wantStatus {
+				t.Errorf("expected status %d, got %d\nstdout:\n%s\nstderr:\n%s", tc.wantStatus, status, stdout.String(), stderr.String())
+			}
+			for _, want := range tc.wantOutput {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("expected %q in the output, got:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
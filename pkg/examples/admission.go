@@ -0,0 +1,395 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	plugincel "k8s.io/apiserver/pkg/admission/plugin/cel"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/generic"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/matching"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/validating"
+	"k8s.io/apiserver/pkg/admission/plugin/webhook/matchconditions"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	"k8s.io/apiserver/pkg/cel/environment"
+	"k8s.io/apiserver/pkg/warning"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+)
+
+// AdmissionResult is the outcome of a request of an admission scenario.
+type AdmissionResult struct {
+	// Scenario is the name of the scenario.
+	Scenario string
+	// Object is the file holding the object of the request, and Index the
+	// document of the file.
+	Object string
+	Index  int
+	// Allowed is set when the request was admitted, otherwise Message tells
+	// why it was denied.
+	Allowed bool
+	Message string
+	// Warnings returned for the request, by bindings with the Warn
+	// validation action.
+	Warnings []string
+}
+
+func (r AdmissionResult) String() string {
+	outcome := "admitted"
+	if !r.Allowed {
+		outcome = "denied: " + r.Message
+	}
+	return fmt.Sprintf("%s: %s (document %d) %s", r.Scenario, r.Object, r.Index, outcome)
+}
+
+// checkAdmissionScenarios runs the admission scenarios of a file holding a
+// ValidatingAdmissionPolicy.
+func (c *Checker) checkAdmissionScenarios(result *FileResult, docs [][]byte, scenarios []AdmissionScenario) {
+	var policy *admissionregistration.ValidatingAdmissionPolicy
+	for _, data := range docs {
+		gvk, err := documentKind(data)
+		if err != nil {
+			continue
+		}
+		if obj, err := decodeObject(data, gvk); err == nil {
+			if p, ok := obj.(*admissionregistration.ValidatingAdmissionPolicy); ok {
+				if policy != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("admission scenarios require a single ValidatingAdmissionPolicy per file"))
+					return
+				}
+				policy = p
+			}
+		}
+	}
+	if policy == nil {
+		result.Errors = append(result.Errors, fmt.Errorf("admission scenarios require a ValidatingAdmissionPolicy"))
+		return
+	}
+	for _, scenario := range scenarios {
+		results, errs := c.checkAdmission(filepath.Dir(result.Path), policy, scenario)
+		result.Admission = append(result.Admission, results...)
+		result.Errors = append(result.Errors, errs...)
+	}
+}
+
+// checkAdmission evaluates a ValidatingAdmissionPolicy of the examples in dir
+// against the requests of scenario, as the ValidatingAdmissionPolicy admission
+// plugin of the API server would, and reports the requests whose outcome is
+// not the expected one.
+func (c *Checker) checkAdmission(dir string, obj *admissionregistration.ValidatingAdmissionPolicy, scenario AdmissionScenario) ([]AdmissionResult, []error) {
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+	if err := legacyscheme.Scheme.Convert(obj, policy, nil); err != nil {
+		return nil, []error{err}
+	}
+	hook := validating.PolicyHook{
+		Policy:    policy,
+		Evaluator: compilePolicy(policy),
+	}
+
+	for _, file := range scenario.Bindings {
+		objs, err := c.decodeFile(dir, file)
+		if err != nil {
+			return nil, []error{err}
+		}
+		for _, obj := range objs {
+			internal, ok := obj.(*admissionregistration.ValidatingAdmissionPolicyBinding)
+			if !ok {
+				return nil, []error{fmt.Errorf("%s: expected ValidatingAdmissionPolicyBinding, got %T", file, obj)}
+			}
+			binding := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+			if err := legacyscheme.Scheme.Convert(internal, binding, nil); err != nil {
+				return nil, []error{err}
+			}
+			if binding.Spec.PolicyName != policy.Name {
+				return nil, []error{fmt.Errorf("%s: binding %s is for policy %s, not %s", file, binding.Name, binding.Spec.PolicyName, policy.Name)}
+			}
+			hook.Bindings = append(hook.Bindings, binding)
+		}
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	if policy.Spec.ParamKind != nil {
+		informer, scope, err := c.paramInformer(dir, policy.Spec.ParamKind, scenario.Params, stop)
+		if err != nil {
+			return nil, []error{err}
+		}
+		hook.ParamInformer = informer
+		hook.ParamScope = scope
+	}
+
+	var namespaces []runtime.Object
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range scenario.Namespaces {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns.Name, Labels: ns.Labels}}
+		namespaces = append(namespaces, namespace)
+		if err := indexer.Add(namespace); err != nil {
+			return nil, []error{err}
+		}
+	}
+	matcher := matching.NewMatcher(corev1listers.NewNamespaceLister(indexer), fake.NewSimpleClientset(namespaces...))
+	dispatcher := validating.NewDispatcher(authorizerfactory.NewAlwaysAllowAuthorizer(), generic.NewPolicyMatcher(matcher))
+	objectInterfaces := admission.NewObjectInterfacesFromScheme(legacyscheme.Scheme)
+
+	var results []AdmissionResult
+	var errs []error
+	for _, request := range scenario.Requests {
+		docs, err := ReadConfigFile(c.resolvePath(dir, request.Object))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for i, data := range docs {
+			attr, err := admissionAttributes(data, request.Namespace, c.typeChecker().RestMapper)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: document %d: %v", request.Object, i, err))
+				continue
+			}
+			// the namespace of a request exists, without labels unless the
+			// scenario declares it
+			if ns := attr.GetNamespace(); ns != "" {
+				if _, exists, _ := indexer.GetByKey(ns); !exists {
+					if err := indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}); err != nil {
+						errs = append(errs, err)
+						continue
+					}
+				}
+			}
+			recorder := &warningRecorder{}
+			ctx := warning.WithWarningRecorder(context.Background(), recorder)
+			result := AdmissionResult{
+				Scenario: scenario.Name,
+				Object:   request.Object,
+				Index:    i,
+				Allowed:  true,
+			}
+			if err := dispatcher.Dispatch(ctx, attr, objectInterfaces, []validating.PolicyHook{hook}); err != nil {
+				result.Allowed = false
+				result.Message = err.Error()
+			}
+			result.Warnings = recorder.warnings
+			results = append(results, result)
+
+			switch {
+			case request.Expect == AdmissionAdmit && !result.Allowed:
+				errs = append(errs, fmt.Errorf("%s: expected %s to be admitted, got %s", scenario.Name, request.Object, result.Message))
+			case request.Expect == AdmissionDeny && result.Allowed:
+				errs = append(errs, fmt.Errorf("%s: expected %s to be denied, it was admitted", scenario.Name, request.Object))
+			case request.Expect == AdmissionDeny && !strings.Contains(result.Message, request.Message):
+				errs = append(errs, fmt.Errorf("%s: expected %s to be denied with %q, got %q", scenario.Name, request.Object, request.Message, result.Message))
+			}
+		}
+	}
+	return results, errs
+}
+
+// admissionAttributes returns the attributes of a request creating the
+// object held by a JSON document in namespace, which defaults to the
+// namespace of the object, or "default" for a namespaced one.
+func admissionAttributes(data []byte, namespace string, mapper meta.RESTMapper) (admission.Attributes, error) {
+	gvk, err := documentKind(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := decodeObject(data, gvk)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = accessor.GetNamespace()
+	}
+	if namespace == "" && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace = metav1.NamespaceDefault
+	}
+	accessor.SetNamespace(namespace)
+	return admission.NewAttributesRecord(obj, nil, gvk, namespace, accessor.GetName(), mapping.Resource, "", admission.Create, &metav1.CreateOptions{}, false, exampleUser), nil
+}
+
+// paramInformer returns an informer serving the parameter resources held by
+// files, which must be of the kind of paramKind, along with the scope of that
+// kind.
+func (c *Checker) paramInformer(dir string, paramKind *admissionregistrationv1.ParamKind, files []string, stop <-chan struct{}) (informers.GenericInformer, meta.RESTScope, error) {
+	gv, err := schema.ParseGroupVersion(paramKind.APIVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	crd := c.customResourceDefinitions()[gv.WithKind(paramKind.Kind).GroupKind()]
+	if crd == nil {
+		// the API server denies requests when the kind is unknown
+		return nil, nil, nil
+	}
+	scope := meta.RESTScopeRoot
+	if crd.Spec.Scope == apiextensions.NamespaceScoped {
+		scope = meta.RESTScopeNamespace
+	}
+
+	var params []runtime.Object
+	for _, file := range files {
+		docs, err := ReadConfigFile(c.resolvePath(dir, file))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, data := range docs {
+			param := &unstructured.Unstructured{}
+			if err := json.Unmarshal(data, &param.Object); err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", file, err)
+			}
+			if param.GroupVersionKind() != gv.WithKind(paramKind.Kind) {
+				return nil, nil, fmt.Errorf("%s: expected %s, got %s", file, gv.WithKind(paramKind.Kind), param.GroupVersionKind())
+			}
+			if scope == meta.RESTScopeNamespace && param.GetNamespace() == "" {
+				param.SetNamespace(metav1.NamespaceDefault)
+			}
+			params = append(params, param)
+		}
+	}
+
+	gvr := gv.WithResource(crd.Spec.Names.Plural)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: paramKind.Kind + "List"}, params...)
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	informer := factory.ForResource(gvr)
+	factory.Start(stop)
+	factory.WaitForCacheSync(stop)
+	return informer, scope, nil
+}
+
+// decodeFile decodes the documents of an example file into internal objects.
+func (c *Checker) decodeFile(dir, file string) ([]runtime.Object, error) {
+	docs, err := ReadConfigFile(c.resolvePath(dir, file))
+	if err != nil {
+		return nil, err
+	}
+	var objs []runtime.Object
+	for i, data := range docs {
+		gvk, err := documentKind(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", file, i, err)
+		}
+		obj, err := decodeObject(data, gvk)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %v", file, i, err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// resolvePath returns the path of a file named relative to the examples
+// directory dir. Files missing from a localized directory are taken from the
+// matching English one.
+func (c *Checker) resolvePath(dir, file string) string {
+	path := filepath.Join(dir, file)
+	if _, err := os.Stat(path); err == nil || c.FallbackRoot == "" {
+		return path
+	}
+	rel, err := filepath.Rel(c.Root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(c.FallbackRoot, rel)
+}
+
+// warningRecorder collects the warnings returned for a request.
+type warningRecorder struct {
+	warnings []string
+}
+
+func (r *warningRecorder) AddWarning(_, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
+var compositionEnvTemplate = func() *plugincel.CompositionEnv {
+	env, err := plugincel.NewCompositionEnv(plugincel.VariablesTypeName, environment.MustBaseEnvSet(environment.DefaultCompatibilityVersion()))
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// compilePolicy compiles the expressions of a policy into the evaluator of
+// the ValidatingAdmissionPolicy admission plugin, as the plugin does.
+func compilePolicy(policy *admissionregistrationv1.ValidatingAdmissionPolicy) validating.Validator {
+	hasParam := policy.Spec.ParamKind != nil
+	optionalVars := plugincel.OptionalVariableDeclarations{HasParams: hasParam, HasAuthorizer: true}
+	expressionOptionalVars := plugincel.OptionalVariableDeclarations{HasParams: hasParam, HasAuthorizer: false}
+	failurePolicy := policy.Spec.FailurePolicy
+
+	compiler := plugincel.NewCompositedCompilerFromTemplate(compositionEnvTemplate)
+	variables := make([]plugincel.NamedExpressionAccessor, len(policy.Spec.Variables))
+	for i, v := range policy.Spec.Variables {
+		variables[i] = &validating.Variable{Name: v.Name, Expression: v.Expression}
+	}
+	compiler.CompileAndStoreVariables(variables, optionalVars, environment.StoredExpressions)
+
+	var matcher matchconditions.Matcher
+	if len(policy.Spec.MatchConditions) > 0 {
+		matchConditions := make([]plugincel.ExpressionAccessor, len(policy.Spec.MatchConditions))
+		for i := range policy.Spec.MatchConditions {
+			matchConditions[i] = (*matchconditions.MatchCondition)(&policy.Spec.MatchConditions[i])
+		}
+		matcher = matchconditions.NewMatcher(compiler.Compile(matchConditions, optionalVars, environment.StoredExpressions), failurePolicy, "policy", "validate", policy.Name)
+	}
+
+	validations := make([]plugincel.ExpressionAccessor, len(policy.Spec.Validations))
+	messageExpressions := make([]plugincel.ExpressionAccessor, len(policy.Spec.Validations))
+	for i, v := range policy.Spec.Validations {
+		validations[i] = &validating.ValidationCondition{Expression: v.Expression, Message: v.Message, Reason: v.Reason}
+		if v.MessageExpression != "" {
+			messageExpressions[i] = &validating.MessageExpressionCondition{MessageExpression: v.MessageExpression}
+		}
+	}
+	auditAnnotations := make([]plugincel.ExpressionAccessor, len(policy.Spec.AuditAnnotations))
+	for i, a := range policy.Spec.AuditAnnotations {
+		auditAnnotations[i] = &validating.AuditAnnotationCondition{Key: a.Key, ValueExpression: a.ValueExpression}
+	}
+
+	return validating.NewValidator(
+		compiler.Compile(validations, optionalVars, environment.StoredExpressions),
+		matcher,
+		compiler.Compile(auditAnnotations, optionalVars, environment.StoredExpressions),
+		compiler.Compile(messageExpressions, expressionOptionalVars, environment.StoredExpressions),
+		failurePolicy,
+	)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+)
+
+const replicaLimitBinding = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: replica-limit
+spec:
+  policyName: replica-limit
+  validationActions: [Deny]
+`
+
+// replicasDeployment is a Deployment formatted with its number of replicas.
+const replicasDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: %d
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+func TestCheckAdmission(t *testing.T) {
+	kubeVersion, err := TargetVersion("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"binding.yaml": replicaLimitBinding,
+		"small.yaml":   fmt.Sprintf(replicasDeployment, 3),
+		"large.yaml":   fmt.Sprintf(replicasDeployment, 10),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checker := NewChecker(dir, kubeVersion)
+
+	obj, _ := decodeTestObject(t, fmt.Sprintf(replicaLimitPolicy, "object.spec.replicas", "variables.replicas <= 5"))
+	policy := obj.(*admissionregistration.ValidatingAdmissionPolicy)
+
+	for _, tc := range []struct {
+		name        string
+		request     AdmissionRequest
+		wantAllowed bool
+		wantErrors  int
+	}{{
+		name:        "admitted",
+		request:     AdmissionRequest{Object: "small.yaml", Expect: AdmissionAdmit},
+		wantAllowed: true,
+	}, {
+		name:    "denied",
+		request: AdmissionRequest{Object: "large.yaml", Expect: AdmissionDeny, Message: "variables.replicas <= 5"},
+	}, {
+		name:       "unexpectedly denied",
+		request:    AdmissionRequest{Object: "large.yaml", Expect: AdmissionAdmit},
+		wantErrors: 1,
+	}, {
+		name:        "unexpectedly admitted",
+		request:     AdmissionRequest{Object: "small.yaml", Expect: AdmissionDeny},
+		wantAllowed: true,
+		wantErrors:  1,
+	}, {
+		name:       "denied with another message",
+		request:    AdmissionRequest{Object: "large.yaml", Expect: AdmissionDeny, Message: "too many replicas"},
+		wantErrors: 1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			scenario := AdmissionScenario{
+				Name:     tc.name,
+				Bindings: []string{"binding.yaml"},
+				Requests: []AdmissionRequest{tc.request},
+			}
+			results, errs := checker.checkAdmission(dir, policy, scenario)
+			if len(errs) != tc.wantErrors {
+				t.Errorf("expected %d errors, got %v", tc.wantErrors, errs)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected a result, got %v", results)
+			}
+			if results[0].Allowed != tc.wantAllowed {
+				t.Errorf("expected the request to be admitted: %v, got %s", tc.wantAllowed, results[0])
+			}
+		})
+	}
+}
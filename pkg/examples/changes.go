@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChangedFiles returns the files under dir changed since the git revision
+// base, such as the target branch of a pull request: the files differing
+// between the merge base of base and HEAD and the working tree, including
+// deleted ones, and the untracked files.
+func ChangedFiles(dir, base string) ([]string, error) {
+	mergeBase, err := git(dir, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git(dir, "diff", "--name-only", "--no-renames", "--relative", "-z", strings.TrimSpace(mergeBase), "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, name := range strings.Split(diff+untracked, "\x00") {
+		if name != "" {
+			changed = append(changed, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	return changed, nil
+}
+
+// git runs a git command in dir and returns its output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// CheckChanged checks the example files under the root directory changed
+// since the git revision base, and the files referring to them, calling fn
+// with the result of each. A file refers to another when its manifest lists
+// it as context or in an admission scenario, or when its objects refer to
+// objects of the other file. Every file of a manifest or of a golden file
+// that changed is checked as well. It returns the number of files checked.
+func (c *Checker) CheckChanged(base string, fn func(FileResult)) (int, error) {
+	changed, err := ChangedFiles(c.Root, base)
+	if err != nil {
+		return 0, err
+	}
+	if c.FallbackRoot != "" {
+		// the manifests and golden files of the English examples apply to
+		// the localized directories without their own
+		english, err := ChangedFiles(c.FallbackRoot, base)
+		if err != nil {
+			return 0, err
+		}
+		for _, path := range english {
+			if filepath.Base(path) != ManifestFile && filepath.Base(filepath.Dir(path)) != GoldenDir {
+				continue
+			}
+			if rel, err := filepath.Rel(c.FallbackRoot, path); err == nil {
+				changed = append(changed, filepath.Join(c.Root, rel))
+			}
+		}
+	}
+
+	paths, changedFiles, selected, err := c.affectedFiles(changed)
+	if err != nil {
+		return 0, err
+	}
+	var results []FileResult
+	c.CheckFiles(paths, func(r FileResult) {
+		results = append(results, r)
+	})
+	for path := range referringFiles(results, changedFiles) {
+		selected[path] = true
+	}
+
+	var checked int
+	for _, r := range results {
+		if selected[r.Path] {
+			checked++
+			fn(r)
+		}
+	}
+	return checked, nil
+}
+
+// affectedFiles returns the example files to check for the changed files:
+// every file of the directories of the changed ones, whose objects the
+// changed files may refer to. It also returns the changed example files,
+// including those of changed golden files, and the files to report: the
+// changed ones, those of changed manifests and those referring to changed
+// files through their manifest.
+func (c *Checker) affectedFiles(changed []string) (paths []string, changedFiles, selected map[string]bool, err error) {
+	changedFiles = map[string]bool{}
+	dirs := map[string]bool{}
+	wholeDirs := map[string]bool{}
+	for _, path := range changed {
+		dir := filepath.Dir(path)
+		switch ext := filepath.Ext(path); {
+		case filepath.Base(path) == ManifestFile:
+			wholeDirs[dir] = true
+		case filepath.Base(dir) == GoldenDir:
+			// the golden file of an example file of the parent directory
+			dir = filepath.Dir(dir)
+			name := strings.TrimSuffix(filepath.Base(path), ext)
+			changedFiles[filepath.Join(dir, name+".yaml")] = true
+			changedFiles[filepath.Join(dir, name+".json")] = true
+		case ext == ".yaml" || ext == ".json":
+			changedFiles[path] = true
+		default:
+			continue
+		}
+		dirs[dir] = true
+	}
+
+	selected = map[string]bool{}
+	for path := range changedFiles {
+		selected[path] = true
+	}
+	manifests := map[string]*Manifest{}
+	var all []string
+	err = WalkConfigFiles(c.Root, func(path string) error {
+		all = append(all, path)
+		dir := filepath.Dir(path)
+		if wholeDirs[dir] {
+			selected[path] = true
+		}
+		manifest, ok := manifests[dir]
+		if !ok {
+			// manifests failing to load are reported when checking their
+			// files
+			manifest, _ = c.loadManifest(dir)
+			manifests[dir] = manifest
+		}
+		if manifest == nil {
+			return nil
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, file := range manifest.Files[name].files() {
+			if changedFiles[filepath.Join(dir, file)] {
+				selected[path] = true
+				dirs[dir] = true
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, path := range all {
+		if dirs[filepath.Dir(path)] {
+			paths = append(paths, path)
+		}
+	}
+	return paths, changedFiles, selected, nil
+}
+
+// files returns the files the expectations name, relative to the directory
+// of their manifest.
+func (f FileExpectations) files() []string {
+	files := append([]string(nil), f.Context...)
+	for _, s := range f.Admission {
+		files = append(files, s.Bindings...)
+		files = append(files, s.Params...)
+		for _, r := range s.Requests {
+			files = append(files, r.Object)
+		}
+	}
+	return files
+}
+
+// referringFiles returns the files whose objects refer to objects of the
+// changed files, among the files of the same directory.
+func referringFiles(results []FileResult, changed map[string]bool) map[string]bool {
+	type fileTarget struct {
+		path   string
+		target referenceTarget
+	}
+	targets := map[string][]fileTarget{}
+	for _, r := range results {
+		if !changed[r.Path] {
+			continue
+		}
+		dir := filepath.Dir(r.Path)
+		for _, doc := range r.Documents {
+			if doc.Object == nil {
+				continue
+			}
+			for _, t := range referenceTargets(doc.Object) {
+				targets[dir] = append(targets[dir], fileTarget{path: r.Path, target: t})
+			}
+		}
+	}
+
+	referring := map[string]bool{}
+	for _, r := range results {
+		dirTargets := targets[filepath.Dir(r.Path)]
+	documents:
+		for _, doc := range r.Documents {
+			if doc.Object == nil {
+				continue
+			}
+			for _, ref := range references(doc.Object) {
+				for _, t := range dirTargets {
+					if t.path != r.Path && t.target.resolves(ref) {
+						referring[r.Path] = true
+						break documents
+					}
+				}
+			}
+		}
+	}
+	return referring
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "content", "en", "examples")
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(args ...string) {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("pods/pod.yaml", "kind: Pod\n")
+	write("pods/service.yaml", "kind: Service\n")
+	write("pods/deleted.yaml", "kind: ConfigMap\n")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("website\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("init", "-q")
+	run("add", "-A")
+	run("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "base")
+	run("branch", "base")
+
+	write("pods/pod.yaml", "kind: Pod\nmetadata:\n  name: pod\n")
+	write("pods/new.yaml", "kind: Secret\n")
+	if err := os.Remove(filepath.Join(dir, "pods", "deleted.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	// changes outside of the directory are left out
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := ChangedFiles(dir, "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(changed)
+	want := []string{
+		filepath.Join(dir, "pods", "deleted.yaml"),
+		filepath.Join(dir, "pods", "new.yaml"),
+		filepath.Join(dir, "pods", "pod.yaml"),
+	}
+	if !slices.Equal(changed, want) {
+		t.Errorf("expected %v, got %v", want, changed)
+	}
+
+	if _, err := ChangedFiles(dir, "unknown"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
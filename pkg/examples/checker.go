@@ -0,0 +1,711 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/validating"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	"k8s.io/apiserver/pkg/endpoints/deprecation"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+	"k8s.io/kubernetes/pkg/capabilities"
+	_ "k8s.io/kubernetes/pkg/features"
+)
+
+// Checker decodes and validates the example files of an examples directory.
+// Its methods are safe for concurrent use once its fields are set.
+type Checker struct {
+	// Root is the examples directory of a locale.
+	Root string
+	// FallbackRoot is the examples directory whose manifests apply to the
+	// directories of Root without one, so that localized examples share
+	// the expectations of the English ones.
+	FallbackRoot string
+	// KubernetesVersion is the release the examples are validated against.
+	KubernetesVersion *version.Version
+	// IgnoreManifests checks the files without the expectations of the
+	// ManifestFile of their directory, for files outside the examples of
+	// the website, where a file named like it is a file like any other.
+	IgnoreManifests bool
+	// UpdateGolden writes the golden files of the examples admitted in the
+	// namespace of their context instead of comparing them.
+	UpdateGolden bool
+	// FailOnWarnings reports the warnings the manifests do not expect as
+	// errors, except for dangling references, see FailOnDanglingReferences.
+	// They are only reported as warnings otherwise.
+	FailOnWarnings bool
+	// FailOnDanglingReferences reports the references to objects that are
+	// neither among the examples of the directory nor declared as externals
+	// as errors, unless the manifests expect their warning. They are only
+	// reported as warnings otherwise.
+	FailOnDanglingReferences bool
+	// RoundTrip converts the objects of built-in kinds to every version of
+	// their kind served by KubernetesVersion and back, warning about the
+	// conversions that lose part of them.
+	RoundTrip bool
+
+	// manifestsLock guards manifests.
+	manifestsLock sync.Mutex
+	manifests     map[string]loadedManifest
+
+	crdsOnce          sync.Once
+	crds              map[schema.GroupKind]*apiextensions.CustomResourceDefinition
+	typeCheckerOnce   sync.Once
+	policyTypeChecker *validating.TypeChecker
+
+	builtinSchemasOnce    sync.Once
+	builtinSchemaResolver *resolver.DefinitionsSchemaResolver
+}
+
+// FileResult is the outcome of checking an example file.
+type FileResult struct {
+	// Path of the file.
+	Path string
+	// Skipped is the reason the file was not validated.
+	Skipped string
+	// Errors concern the file as a whole, such as a malformed document or
+	// a mismatch with the expectations of the file.
+	Errors []error
+	// Documents holds the outcome of each document of the file.
+	Documents []DocumentResult
+	// Admission holds the outcome of the requests of the admission
+	// scenarios of the file.
+	Admission []AdmissionResult
+
+	// expectedWarnings are the warnings the manifest of the file expects,
+	// which are only all raised once the references of its objects are
+	// resolved.
+	expectedWarnings []ExpectedWarning
+}
+
+// DocumentResult is the outcome of checking one document of an example file.
+type DocumentResult struct {
+	// Index of the document in the file.
+	Index int
+	// Kind declared by the document.
+	Kind schema.GroupVersionKind
+	// Errors found decoding or validating the document.
+	Errors []error
+	// Rejections are the reasons admission plugins deny the creation of
+	// the valid document for, such as the limits of a LimitRange of its
+	// namespace.
+	Rejections []error
+	// Object is the internal object the document was admitted as, once
+	// defaulted and mutated by admission plugins. It is nil for documents
+	// that were not.
+	Object runtime.Object
+	// PodSecurity is the outcome of evaluating the Pod Security Standards
+	// against the pod or pod template of a valid document. It is nil for
+	// documents without one.
+	PodSecurity *PodSecurity
+	// ExpectedErrors are the errors or rejections of a document the
+	// manifest of the file expects to fail, which do not fail the file.
+	ExpectedErrors []error
+	// Warnings raised validating the document, such as those the API server
+	// returns when creating it.
+	Warnings []Warning
+
+	// source is the root node of the YAML or JSON source of the document,
+	// whose first line is line in the file, for locating its fields.
+	source *yaml.Node
+	line   int
+}
+
+// Warning is raised by a document that is valid but likely not to behave as
+// intended, such as a policy whose expressions do not type check.
+type Warning struct {
+	// FieldRef is the field the warning is about, if known.
+	FieldRef string
+	// Message describes the problem.
+	Message string
+	// Expected is set for the warnings the manifest of the file expects.
+	Expected bool
+	// Location is the position of the field the warning is about in the
+	// file, if known.
+	Location *Location
+}
+
+func (w Warning) String() string {
+	if w.FieldRef == "" {
+		return w.Message
+	}
+	return w.FieldRef + ": " + w.Message
+}
+
+// clone returns a copy of the result whose errors and warnings can be
+// added to without changing those of r.
+func (r FileResult) clone() FileResult {
+	r.Errors = slices.Clone(r.Errors)
+	r.Documents = slices.Clone(r.Documents)
+	for i := range r.Documents {
+		doc := &r.Documents[i]
+		doc.Errors = slices.Clone(doc.Errors)
+		doc.Warnings = slices.Clone(doc.Warnings)
+	}
+	return r
+}
+
+// Failed reports whether any error was found in the file.
+func (r FileResult) Failed() bool {
+	if len(r.Errors) > 0 {
+		return true
+	}
+	for _, doc := range r.Documents {
+		if len(doc.Errors) > 0 || len(doc.Rejections) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewChecker returns a Checker for the examples under root, validating them
+// against Kubernetes release kubeVersion. When root is the examples directory
+// of a locale other than English, the English examples provide the manifests
+// for its directories without one.
+func NewChecker(root string, kubeVersion *version.Version) *Checker {
+	// Allow privileged containers, as kube-apiserver --allow-privileged does.
+	// The Pod Security Standards they violate are reported separately.
+	capabilities.SetForTests(capabilities.Capabilities{
+		AllowPrivileged: true,
+	})
+
+	c := &Checker{
+		Root:              root,
+		KubernetesVersion: kubeVersion,
+		manifests:         map[string]loadedManifest{},
+	}
+	if abs, err := filepath.Abs(root); err == nil && filepath.Base(abs) == "examples" {
+		english := filepath.Join(filepath.Dir(filepath.Dir(abs)), "en", "examples")
+		if info, err := os.Stat(english); err == nil && info.IsDir() && english != abs {
+			c.FallbackRoot = english
+		}
+	}
+	return c
+}
+
+// Check walks the root directory and checks every example file found,
+// calling fn with the result of each once the references between the
+// objects of each directory are resolved.
+func (c *Checker) Check(fn func(FileResult)) error {
+	var paths []string
+	err := WalkConfigFiles(c.Root, func(path string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	c.CheckFiles(paths, fn)
+	return err
+}
+
+// CheckFiles checks the example files at paths, calling fn with the result
+// of each once the references between the objects of the files of each
+// directory are resolved. Files that are not checked along with them are
+// not looked up.
+func (c *Checker) CheckFiles(paths []string, fn func(FileResult)) {
+	var results []FileResult
+	for _, path := range paths {
+		results = append(results, c.CheckFile(path))
+	}
+	c.ResolveReferences(results)
+	for _, r := range results {
+		fn(r)
+	}
+}
+
+// Directory holds the example files of a directory, for them to be checked
+// concurrently, such as by parallel subtests. Each file is checked once,
+// however many of the others need its objects to resolve their references.
+type Directory struct {
+	// Path of the directory.
+	Path string
+	// Files are the paths of the example files of the directory.
+	Files []string
+
+	checker *Checker
+	once    []sync.Once
+	results []FileResult
+}
+
+// Directories returns the directories holding the example files under the
+// root directory, in the order they are walked.
+func (c *Checker) Directories() ([]*Directory, error) {
+	var dirs []*Directory
+	byPath := map[string]*Directory{}
+	err := WalkConfigFiles(c.Root, func(path string) error {
+		d, ok := byPath[filepath.Dir(path)]
+		if !ok {
+			d = &Directory{Path: filepath.Dir(path), checker: c}
+			byPath[d.Path] = d
+			dirs = append(dirs, d)
+		}
+		d.Files = append(d.Files, path)
+		return nil
+	})
+	for _, d := range dirs {
+		d.once = make([]sync.Once, len(d.Files))
+		d.results = make([]FileResult, len(d.Files))
+	}
+	return dirs, err
+}
+
+// CheckFile checks the i-th file of the directory, resolving the references
+// of its objects to the objects of the other files.
+func (d *Directory) CheckFile(i int) FileResult {
+	r := d.result(i).clone()
+	results := make([]FileResult, len(d.Files))
+	for j := range d.Files {
+		results[j] = d.result(j)
+	}
+	d.checker.resolveReferences(&r, d.checker.directoryTargets(d.Path, results))
+	return r
+}
+
+// result returns the result of checking the i-th file of the directory,
+// before resolving the references of its objects.
+func (d *Directory) result(i int) FileResult {
+	d.once[i].Do(func() {
+		d.results[i] = d.checker.CheckFile(d.Files[i])
+	})
+	return d.results[i]
+}
+
+// CheckFile checks the example file at path.
+func (c *Checker) CheckFile(path string) FileResult {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileResult{Path: path, Errors: []error{err}}
+	}
+	return c.CheckData(path, data)
+}
+
+// CheckData checks data, the content of an example file at path, such as
+// a manifest read from the standard input. The file gets the expectations
+// of the manifest of the directory of path, if any. The expected warnings
+// that are not raised are reported once the references of its objects are
+// resolved, see ResolveReferences.
+func (c *Checker) CheckData(path string, data []byte) FileResult {
+	result := FileResult{Path: path}
+	manifest, err := c.manifest(filepath.Dir(path))
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	if manifest.Skip != "" {
+		result.Skipped = manifest.Skip
+		return result
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	expected := manifest.Files[name]
+	reason, err := expected.SkipReason(c.KubernetesVersion)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	if reason != "" {
+		result.Skipped = reason
+		return result
+	}
+
+	docs, sources, lines, err := splitConfigData(path, data)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	for i := range docs {
+		docs[i] = expected.substitute(docs[i])
+		sources[i] = expected.substitute(sources[i])
+	}
+	if len(expected.Kinds) > 0 && len(expected.Kinds) != len(docs) {
+		result.Errors = append(result.Errors, fmt.Errorf("number of expected kinds (%v) doesn't match number of docs in YAML (%v)", len(expected.Kinds), len(docs)))
+		return result
+	}
+	var context []runtime.Object
+	for _, file := range expected.Context {
+		objs, err := c.decodeFile(filepath.Dir(path), file)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("context: %v", err))
+			return result
+		}
+		context = append(context, objs...)
+	}
+	// the feature gates are global, files setting some are checked alone
+	if len(expected.FeatureGates) > 0 {
+		featureGatesLock.Lock()
+		defer featureGatesLock.Unlock()
+	} else {
+		featureGatesLock.RLock()
+		defer featureGatesLock.RUnlock()
+	}
+	restore, err := setFeatureGates(expected.FeatureGates)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	defer restore()
+
+	for i, data := range docs {
+		expectedKind := ""
+		if len(expected.Kinds) > 0 {
+			expectedKind = expected.Kinds[i]
+		}
+		doc := c.checkDocument(data, sources[i], expectedKind, context)
+		doc.Index = i
+		doc.line = lines[i]
+		if doc.source = parseSourceNode(sources[i]); doc.source == nil {
+			// json documents are their own source
+			doc.source = parseSourceNode(data)
+		}
+		result.Documents = append(result.Documents, doc)
+	}
+	if len(expected.Context) > 0 {
+		c.checkGolden(&result, c.UpdateGolden)
+	}
+	result.expectedWarnings = expected.Warnings
+	checkWarnings(&result, c.FailOnWarnings)
+	checkExpectedFailure(&result, expected.Expect)
+	checkPodSecurity(&result, expected.PodSecurity)
+	if len(expected.Admission) > 0 && !result.Failed() {
+		c.checkAdmissionScenarios(&result, docs, expected.Admission)
+	}
+	locateErrors(&result)
+	return result
+}
+
+// checkWarnings marks the warnings of the documents of a file that it
+// expects and, when fail is set, reports those that were not expected.
+func checkWarnings(result *FileResult, fail bool) {
+	for i := range result.Documents {
+		doc := &result.Documents[i]
+		for k := range doc.Warnings {
+			w := &doc.Warnings[k]
+			w.Expected = expectedWarning(result.expectedWarnings, *w)
+			if !w.Expected && fail {
+				doc.Errors = append(doc.Errors, fmt.Errorf("unexpected warning: %s", w))
+			}
+		}
+	}
+}
+
+// expectedWarning reports whether w is among the expected warnings.
+func expectedWarning(expected []ExpectedWarning, w Warning) bool {
+	for _, e := range expected {
+		if e.Matches(w.FieldRef, w.Message) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRaisedWarnings reports the warnings a file expects that none of its
+// documents raised.
+func checkRaisedWarnings(result *FileResult) {
+	for _, e := range result.expectedWarnings {
+		raised := false
+		for _, doc := range result.Documents {
+			for _, w := range doc.Warnings {
+				raised = raised || e.Matches(w.FieldRef, w.Message)
+			}
+		}
+		if !raised {
+			result.Errors = append(result.Errors, fmt.Errorf("expected warning %q on %q was not raised", e.Message, e.FieldRef))
+		}
+	}
+}
+
+// checkExpectedFailure checks that the document a file expects to fail
+// does, with the expected errors, which then no longer fail the file.
+func checkExpectedFailure(result *FileResult, expect *ExpectedFailure) {
+	if expect == nil {
+		return
+	}
+	if expect.Document < 0 || expect.Document >= len(result.Documents) {
+		result.Errors = append(result.Errors, fmt.Errorf("expected failure of non-existent document %d", expect.Document))
+		return
+	}
+	doc := &result.Documents[expect.Document]
+	errs := doc.Errors
+	if expect.Result == ExpectRejected {
+		if len(doc.Errors) > 0 {
+			// an invalid document is not admitted either, its errors are
+			// reported as such
+			return
+		}
+		errs = doc.Rejections
+	}
+	if len(errs) == 0 {
+		result.Errors = append(result.Errors, fmt.Errorf("document %d was expected to be %s but was not", expect.Document, expect.Result))
+		return
+	}
+	found := true
+	for _, e := range expect.Errors {
+		matched := false
+		for _, err := range errs {
+			if e.Matches(err) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			found = false
+			result.Errors = append(result.Errors, fmt.Errorf("expected error %q on %q was not raised by document %d", e.Message, e.Field, expect.Document))
+		}
+	}
+	if !found {
+		return
+	}
+	doc.ExpectedErrors = errs
+	if expect.Result == ExpectRejected {
+		doc.Rejections = nil
+	} else {
+		doc.Errors = nil
+	}
+}
+
+// checkDocument decodes a JSON document into the internal type matching its
+// apiVersion and kind, and validates it. Custom resources are validated
+// against the CustomResourceDefinition of their kind, and the built-in kinds
+// ValidateObject does not validate are validated against their OpenAPI
+// schema. Unknown and duplicate fields of the document, or of its YAML
+// source, are reported as well, and so are versions of built-in APIs the
+// target release does not serve. Valid objects are then admitted in a
+// namespace holding the objects of context, as the LimitRanger and
+// ResourceQuota admission plugins would, and their pods are evaluated
+// against the Pod Security Standards.
+func (c *Checker) checkDocument(data, source []byte, expectedKind string, context []runtime.Object) DocumentResult {
+	result := DocumentResult{}
+	gvk, err := documentKind(data)
+	result.Kind = gvk
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	if expectedKind != "" && gvk.Kind != expectedKind {
+		result.Errors = append(result.Errors, fmt.Errorf("expected kind %s, got %s", expectedKind, gvk.Kind))
+		return result
+	}
+	var crd *apiextensions.CustomResourceDefinition
+	if !legacyscheme.Scheme.Recognizes(gvk) {
+		crd = c.customResourceDefinitions()[gvk.GroupKind()]
+	}
+	result.Errors = append(result.Errors, strictDecodingErrors(data, source, gvk, crd)...)
+	if crd != nil {
+		for _, err := range ValidateCustomResource(data, gvk, crd) {
+			result.Errors = append(result.Errors, err)
+		}
+		if msg := customResourceDeprecationWarning(crd, gvk.Version); msg != "" {
+			result.Warnings = append(result.Warnings, Warning{Message: msg})
+		}
+		return result
+	}
+	if err := notServedError(gvk, c.KubernetesVersion); err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	if msg := c.deprecationWarning(gvk); msg != "" {
+		result.Warnings = append(result.Warnings, Warning{Message: msg})
+	}
+	if !hasValidation(gvk) {
+		if s, err := c.builtinSchemas().ResolveSchema(gvk); err == nil {
+			for _, err := range ValidateOpenAPI(data, s) {
+				result.Errors = append(result.Errors, err)
+			}
+			return result
+		}
+	}
+
+	obj, err := decodeObject(data, gvk)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+	if c.RoundTrip {
+		result.Warnings = append(result.Warnings, roundTripWarnings(obj, gvk.GroupKind(), c.KubernetesVersion)...)
+	}
+	ranges, err := limitRanges(context)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("context: %v", err))
+		return result
+	}
+	if result.Rejections = limitRangerMutate(obj, ranges); len(result.Rejections) > 0 {
+		return result
+	}
+	errs, warnings := ValidateObject(obj, gvk.GroupVersion())
+	for _, err := range errs {
+		result.Errors = append(result.Errors, err)
+	}
+	if len(result.Errors) == 0 {
+		result.Rejections = limitRangerValidate(obj, ranges)
+	}
+	if len(result.Errors) == 0 && len(result.Rejections) == 0 {
+		quotas, err := resourceQuotas(context)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("context: %v", err))
+			return result
+		}
+		result.Rejections = quotaValidate(obj, quotas)
+	}
+	if len(result.Errors) == 0 && len(result.Rejections) == 0 {
+		result.Object = obj
+	}
+	if len(result.Errors) == 0 {
+		if result.PodSecurity, err = podSecurity(obj, gvk, c.KubernetesVersion); err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("unable to evaluate the Pod Security Standards: %v", err))
+		}
+	}
+	for _, w := range warnings {
+		result.Warnings = append(result.Warnings, Warning{Message: w})
+	}
+	if policy, ok := obj.(*admissionregistration.ValidatingAdmissionPolicy); ok && len(result.Errors) == 0 {
+		warnings, err := c.typeCheckPolicy(policy)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("unable to type check policy: %v", err))
+		}
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	return result
+}
+
+// deprecationWarning returns the warning the API server returns for requests
+// to a deprecated version of a built-in API, or an empty string.
+func (c *Checker) deprecationWarning(gvk schema.GroupVersionKind) string {
+	obj, err := newExternalObject(gvk)
+	if err != nil {
+		return ""
+	}
+	if !deprecation.IsDeprecated(obj, int(c.KubernetesVersion.Major()), int(c.KubernetesVersion.Minor())) {
+		return ""
+	}
+	return deprecation.WarningMessage(obj)
+}
+
+// customResourceDeprecationWarning returns the warning the API server
+// returns for requests to a deprecated version of a custom resource, or an
+// empty string.
+func customResourceDeprecationWarning(crd *apiextensions.CustomResourceDefinition, version string) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Name != version || !v.Deprecated {
+			continue
+		}
+		if v.DeprecationWarning != nil {
+			return *v.DeprecationWarning
+		}
+		return fmt.Sprintf("%s/%s %s is deprecated", crd.Spec.Group, version, crd.Spec.Names.Kind)
+	}
+	return ""
+}
+
+// loadedManifest is the outcome of loading the manifest of a directory.
+type loadedManifest struct {
+	manifest *Manifest
+	err      error
+}
+
+// manifest returns the manifest for the examples in dir. A manifest that
+// fails to load is loaded once, and its error returned to every caller, for
+// every file of its directory to fail.
+func (c *Checker) manifest(dir string) (*Manifest, error) {
+	c.manifestsLock.Lock()
+	defer c.manifestsLock.Unlock()
+	loaded, ok := c.manifests[dir]
+	if !ok {
+		loaded.manifest, loaded.err = c.loadManifest(dir)
+		c.manifests[dir] = loaded
+	}
+	return loaded.manifest, loaded.err
+}
+
+func (c *Checker) loadManifest(dir string) (*Manifest, error) {
+	if c.IgnoreManifests {
+		return &Manifest{}, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil || c.FallbackRoot == "" {
+		return LoadManifest(dir)
+	}
+	rel, err := filepath.Rel(c.Root, dir)
+	if err != nil {
+		return nil, err
+	}
+	return LoadManifest(filepath.Join(c.FallbackRoot, rel))
+}
+
+// TargetVersion parses the Kubernetes release the examples are validated
+// against. When release is empty, it is the release matching the
+// k8s.io/apimachinery dependency.
+func TargetVersion(release string) (*version.Version, error) {
+	if release != "" {
+		v, err := version.ParseGeneric(release)
+		if err != nil {
+			return nil, err
+		}
+		return version.MajorMinor(v.Major(), v.Minor()), nil
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path != "k8s.io/apimachinery" {
+				continue
+			}
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			// k8s.io/apimachinery v0.X.Y is released with Kubernetes v1.X.Y
+			v, err := version.ParseSemantic(dep.Version)
+			if err != nil {
+				return nil, err
+			}
+			return version.MajorMinor(1, v.Minor()), nil
+		}
+	}
+	return nil, fmt.Errorf("unable to determine the Kubernetes release to validate against")
+}
+
+// featureGatesLock is held for writing while checking a file setting feature
+// gates, and for reading while checking any other file.
+var featureGatesLock sync.RWMutex
+
+// setFeatureGates sets the given feature gates and returns a function
+// restoring their previous values.
+func setFeatureGates(gates map[string]bool) (func(), error) {
+	known := utilfeature.DefaultMutableFeatureGate.GetAll()
+	previous := map[string]bool{}
+	for name := range gates {
+		if _, ok := known[featuregate.Feature(name)]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q", name)
+		}
+		previous[name] = utilfeature.DefaultFeatureGate.Enabled(featuregate.Feature(name))
+	}
+	if err := utilfeature.DefaultMutableFeatureGate.SetFromMap(gates); err != nil {
+		return nil, err
+	}
+	return func() {
+		utilfeature.DefaultMutableFeatureGate.SetFromMap(previous)
+	}, nil
+}
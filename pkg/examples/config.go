@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/apis/audit"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// componentConfigKinds holds the kinds of the configuration files of the
+// control plane components, which ConfigMaps hold for the components to
+// load them. Each has a validator.
+var componentConfigKinds = map[schema.GroupKind]bool{
+	{Group: apiserver.LegacyGroupName, Kind: "EgressSelectorConfiguration"}: true,
+	{Group: apiserver.GroupName, Kind: "EgressSelectorConfiguration"}:       true,
+	{Group: audit.GroupName, Kind: "Policy"}:                                true,
+	{Group: schedulerconfig.GroupName, Kind: "KubeSchedulerConfiguration"}:  true,
+}
+
+// validateEmbeddedConfigs validates the documents held by the data of a
+// ConfigMap that declare the apiVersion and kind of the configuration file
+// of a control plane component. Any other data is opaque and left alone,
+// with a warning for the data that is not YAML.
+func validateEmbeddedConfigs(cm *api.ConfigMap) (field.ErrorList, []string) {
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errors field.ErrorList
+	var warnings []string
+	for _, key := range keys {
+		fldPath := field.NewPath("data").Key(key)
+		docs, err := splitYAMLDocuments([]byte(cm.Data[key]))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: not validated, unable to parse as YAML: %v", fldPath, err))
+			continue
+		}
+		for _, data := range docs {
+			gvk, err := documentKind(data)
+			if err != nil || !componentConfigKinds[gvk.GroupKind()] {
+				continue
+			}
+			obj, err := decodeObject(data, gvk)
+			if err != nil {
+				errors = append(errors, field.Invalid(fldPath, gvk.String(), err.Error()))
+				continue
+			}
+			// the warnings of a component loading its configuration are
+			// logged, not returned to the creator of the ConfigMap
+			errs, _ := ValidateObject(obj, gvk.GroupVersion())
+			for _, err := range errs {
+				errors = append(errors, prefixFieldError(fldPath, err))
+			}
+		}
+	}
+	return errors, warnings
+}
+
+// prefixFieldError returns err with its field relative to fldPath.
+func prefixFieldError(fldPath *field.Path, err *field.Error) *field.Error {
+	prefixed := *err
+	switch {
+	case err.Field == "":
+		prefixed.Field = fldPath.String()
+	case err.Field[0] == '[':
+		prefixed.Field = fldPath.String() + err.Field
+	default:
+		prefixed.Field = fldPath.String() + "." + err.Field
+	}
+	return &prefixed
+}
+
+// aggregateToErrorList turns the errors of a validation function returning an
+// aggregate, as those of component configurations do, into a field.ErrorList.
+func aggregateToErrorList(agg utilerrors.Aggregate) field.ErrorList {
+	if agg == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	for _, err := range agg.Errors() {
+		if fieldErr, ok := err.(*field.Error); ok {
+			errors = append(errors, fieldErr)
+			continue
+		}
+		errors = append(errors, field.InternalError(field.NewPath(""), err))
+	}
+	return errors
+}
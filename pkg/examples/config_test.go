@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"slices"
+	"testing"
+
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+func TestValidateEmbeddedConfigs(t *testing.T) {
+	for kind := range componentConfigKinds {
+		if _, ok := validators[kind]; !ok {
+			t.Errorf("no validator for the configuration files of kind %s", kind)
+		}
+	}
+
+	for _, tc := range []struct {
+		name         string
+		data         string
+		wantFields   []string
+		wantWarnings int
+	}{{
+		name: "valid configuration",
+		data: `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: my-scheduler
+`,
+	}, {
+		name: "invalid configuration",
+		data: `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: ""
+`,
+		wantFields: []string{"data[config].profiles[0].schedulerName"},
+	}, {
+		name: "object other than a configuration",
+		data: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers: []
+`,
+	}, {
+		name: "opaque data",
+		data: "color.good=purple\ncolor.bad=yellow\n",
+	}, {
+		name:         "data that is not YAML",
+		data:         "server: {listen: 80\n",
+		wantWarnings: 1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := &api.ConfigMap{Data: map[string]string{"config": tc.data}}
+			errs, warnings := validateEmbeddedConfigs(cm)
+			if fields := errorFields(errs); !slices.Equal(fields, tc.wantFields) {
+				t.Errorf("expected errors for %v, got %v", tc.wantFields, errs)
+			}
+			if len(warnings) != tc.wantWarnings {
+				t.Errorf("expected %d warnings, got %v", tc.wantWarnings, warnings)
+			}
+		})
+	}
+}
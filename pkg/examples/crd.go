@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	structurallisttype "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/listtype"
+	schemaobjectmeta "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/objectmeta"
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apiextensionsfeatures "k8s.io/apiextensions-apiserver/pkg/features"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// customResourceDefinitions returns the CustomResourceDefinitions found in
+// the examples, keyed by the group and kind of the resources they define,
+// along with those made up from the stubs of the manifests. Those of Root
+// take precedence over those of FallbackRoot, and actual definitions over
+// stubs.
+func (c *Checker) customResourceDefinitions() map[schema.GroupKind]*apiextensions.CustomResourceDefinition {
+	c.crdsOnce.Do(c.loadCustomResourceDefinitions)
+	return c.crds
+}
+
+func (c *Checker) loadCustomResourceDefinitions() {
+	crds := map[schema.GroupKind]*apiextensions.CustomResourceDefinition{}
+	stubs := map[schema.GroupKind]*apiextensions.CustomResourceDefinition{}
+	dirs := map[string]bool{}
+	for _, root := range []string{c.FallbackRoot, c.Root} {
+		if root == "" {
+			continue
+		}
+		// Files and manifests that fail to load are reported when they are
+		// checked themselves.
+		_ = WalkConfigFiles(root, func(path string) error {
+			if dir := filepath.Dir(path); !dirs[dir] {
+				dirs[dir] = true
+				if manifest, err := c.loadManifest(dir); err == nil {
+					for _, stub := range manifest.Stubs {
+						if crd, err := stub.CustomResourceDefinition(); err == nil {
+							stubs[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+						}
+					}
+				}
+			}
+			docs, err := ReadConfigFile(path)
+			if err != nil {
+				return nil
+			}
+			for _, data := range docs {
+				if crd := decodeCustomResourceDefinition(data); crd != nil {
+					crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+				}
+			}
+			return nil
+		})
+	}
+	for gk, crd := range crds {
+		stubs[gk] = crd
+	}
+	c.crds = stubs
+}
+
+// decodeCustomResourceDefinition returns the CustomResourceDefinition a JSON
+// document holds, or nil if it holds anything else.
+func decodeCustomResourceDefinition(data []byte) *apiextensions.CustomResourceDefinition {
+	gvk, err := documentKind(data)
+	if err != nil || gvk.GroupKind() != apiextensions.Kind("CustomResourceDefinition") {
+		return nil
+	}
+	obj, err := decodeObject(data, gvk)
+	if err != nil {
+		return nil
+	}
+	crd, _ := obj.(*apiextensions.CustomResourceDefinition)
+	return crd
+}
+
+// ValidateCustomResource validates a custom resource held by a JSON document
+// the way the apiextensions-apiserver validates it on creation: against the
+// schema, including its x-kubernetes-validations rules, that crd defines for
+// the version of the resource.
+func ValidateCustomResource(data []byte, gvk schema.GroupVersionKind, crd *apiextensions.CustomResourceDefinition) (errors field.ErrorList) {
+	if !apiextensions.HasServedCRDVersion(crd, gvk.Version) {
+		var served []string
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				served = append(served, crd.Spec.Group+"/"+v.Name)
+			}
+		}
+		return field.ErrorList{field.NotSupported(field.NewPath("apiVersion"), gvk.GroupVersion().String(), served)}
+	}
+
+	// integers are decoded as int64, as the schema and its rules expect
+	u := &unstructured.Unstructured{}
+	if err := utiljson.Unmarshal(data, &u.Object); err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath(""), err)}
+	}
+	namespaced := crd.Spec.Scope == apiextensions.NamespaceScoped
+	if namespaced && u.GetNamespace() == "" {
+		u.SetNamespace("default")
+	}
+	errors = append(errors, validation.ValidateObjectMetaAccessor(u, namespaced, validation.NameIsDNSSubdomain, field.NewPath("metadata"))...)
+
+	crv, err := apiextensions.GetSchemaForVersion(crd, gvk.Version)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath(""), err))
+	}
+	if crv == nil || crv.OpenAPIV3Schema == nil {
+		return errors
+	}
+	structural, err := structuralschema.NewStructural(crv.OpenAPIV3Schema)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath(""), fmt.Errorf("schema of %s is not structural: %v", gvk.Version, err)))
+	}
+	structuraldefaulting.Default(u.Object, structural)
+
+	validator, _, err := apiservervalidation.NewSchemaValidator(crv.OpenAPIV3Schema)
+	if err != nil {
+		return append(errors, field.InternalError(field.NewPath(""), err))
+	}
+	errors = append(errors, apiservervalidation.ValidateCustomResource(nil, u.UnstructuredContent(), validator)...)
+	errors = append(errors, schemaobjectmeta.Validate(nil, u.Object, structural, false)...)
+	errors = append(errors, structurallisttype.ValidateListSetsAndMaps(nil, structural, u.Object)...)
+	// rules are only evaluated against objects that pass the schema
+	if len(errors) == 0 {
+		if celValidator := cel.NewValidator(structural, true, celconfig.PerCallLimit); celValidator != nil {
+			celErrors, _ := celValidator.Validate(context.TODO(), nil, structural, u.Object, nil, celconfig.RuntimeCELCostBudget)
+			errors = append(errors, celErrors...)
+		}
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(apiextensionsfeatures.CustomResourceFieldSelectors) {
+		errors = append(errors, validateSelectableFields(u, selectableFieldsForVersion(crd, gvk.Version))...)
+	}
+	return errors
+}
+
+// selectableFieldsForVersion returns the selectable fields of a version of
+// crd, falling back to those defined for all versions.
+func selectableFieldsForVersion(crd *apiextensions.CustomResourceDefinition, version string) []apiextensions.SelectableField {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && len(v.SelectableFields) > 0 {
+			return v.SelectableFields
+		}
+	}
+	return crd.Spec.SelectableFields
+}
+
+// validateSelectableFields checks that each selectable field resolves to at
+// most one value of u, which the API server requires to serve field
+// selectors for the resource.
+func validateSelectableFields(u *unstructured.Unstructured, selectableFields []apiextensions.SelectableField) field.ErrorList {
+	var errors field.ErrorList
+	for _, sf := range selectableFields {
+		parser := jsonpath.New("selectableField")
+		parser.AllowMissingKeys(true)
+		if err := parser.Parse("{" + sf.JSONPath + "}"); err != nil {
+			errors = append(errors, field.Invalid(field.NewPath(""), sf.JSONPath, fmt.Sprintf("invalid selectable field: %v", err)))
+			continue
+		}
+		results, err := parser.FindResults(u.UnstructuredContent())
+		if err != nil {
+			errors = append(errors, field.Invalid(field.NewPath(""), sf.JSONPath, fmt.Sprintf("unable to evaluate selectable field: %v", err)))
+			continue
+		}
+		if len(results) > 1 || (len(results) == 1 && len(results[0]) > 1) {
+			errors = append(errors, field.Invalid(field.NewPath(""), sf.JSONPath, "selectable field resolves to more than one value"))
+		}
+	}
+	return errors
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"slices"
+	"testing"
+)
+
+const crontabDefinition = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  scope: Namespaced
+  names:
+    plural: crontabs
+    singular: crontab
+    kind: CronTab
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              cronSpec:
+                type: string
+              replicas:
+                type: integer
+                minimum: 1
+            x-kubernetes-validations:
+            - rule: "self.replicas <= 10"
+              message: at most 10 replicas
+`
+
+func TestValidateCustomResource(t *testing.T) {
+	data, _ := decodeTestDocument(t, crontabDefinition)
+	crd := decodeCustomResourceDefinition(data)
+	if crd == nil {
+		t.Fatal("the CustomResourceDefinition was not decoded")
+	}
+	if crd.Spec.Names.Kind != "CronTab" {
+		t.Fatalf("unexpected kind: %q", crd.Spec.Names.Kind)
+	}
+	// any other document is not a CustomResourceDefinition
+	if data, _ := decodeTestDocument(t, testConfigMap); decodeCustomResourceDefinition(data) != nil {
+		t.Error("expected no CustomResourceDefinition for a ConfigMap")
+	}
+
+	for _, tc := range []struct {
+		name       string
+		resource   string
+		wantFields []string
+	}{{
+		name: "valid",
+		resource: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  cronSpec: "* * * * */5"
+  replicas: 3
+`,
+	}, {
+		name: "schema",
+		resource: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  cronSpec: 5
+  replicas: 0
+`,
+		wantFields: []string{"spec.cronSpec", "spec.replicas"},
+	}, {
+		name: "rule",
+		resource: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  replicas: 20
+`,
+		wantFields: []string{"spec"},
+	}, {
+		name: "unserved version",
+		resource: `apiVersion: stable.example.com/v2
+kind: CronTab
+metadata:
+  name: crontab
+`,
+		wantFields: []string{"apiVersion"},
+	}, {
+		name: "invalid name",
+		resource: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: Cron_Tab
+`,
+		wantFields: []string{"metadata.name"},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, gvk := decodeTestDocument(t, tc.resource)
+			errs := ValidateCustomResource(data, gvk, crd)
+			if fields := errorFields(errs); !slices.Equal(fields, tc.wantFields) {
+				t.Errorf("expected errors for %v, got %v", tc.wantFields, errs)
+			}
+		})
+	}
+}
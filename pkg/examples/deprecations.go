@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apiserver/pkg/endpoints/deprecation"
+	"sigs.k8s.io/yaml"
+)
+
+// releaseSchedule is the part of data/releases/schedule.yaml and eol.yaml
+// naming the releases they describe.
+type releaseSchedule struct {
+	Schedules []struct {
+		Release string `json:"release"`
+	} `json:"schedules"`
+	Branches []struct {
+		Release string `json:"release"`
+	} `json:"branches"`
+}
+
+// SupportedReleases reads the releases of the schedule.yaml file of dir,
+// such as data/releases, that its eol.yaml file does not list as end of
+// life, from the oldest.
+func SupportedReleases(dir string) ([]*version.Version, error) {
+	var schedule, eol releaseSchedule
+	for file, into := range map[string]*releaseSchedule{"schedule.yaml": &schedule, "eol.yaml": &eol} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, into); err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Join(dir, file), err)
+		}
+	}
+	endOfLife := map[string]bool{}
+	for _, b := range eol.Branches {
+		endOfLife[b.Release] = true
+	}
+
+	var releases []*version.Version
+	for _, s := range schedule.Schedules {
+		if endOfLife[s.Release] {
+			continue
+		}
+		v, err := version.ParseGeneric(s.Release)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Join(dir, "schedule.yaml"), err)
+		}
+		releases = append(releases, version.MajorMinor(v.Major(), v.Minor()))
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no supported release found in %s", dir)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].LessThan(releases[j]) })
+	return releases, nil
+}
+
+// Deprecation is the use of a deprecated or removed API, field or annotation
+// by a document of an example file.
+type Deprecation struct {
+	// Path of the file.
+	Path string
+	// Index of the document in the file.
+	Index int
+	// Kind declared by the document.
+	Kind schema.GroupVersionKind
+	// Message describes the deprecation, as the API server warns about it.
+	Message string
+	// Deprecated lists the supported releases deprecating the API, field or
+	// annotation.
+	Deprecated []*version.Version
+	// Removed lists the supported releases that no longer serve the API.
+	Removed []*version.Version
+}
+
+func (d Deprecation) String() string {
+	s := fmt.Sprintf("%s: document %d (%s): %s", d.Path, d.Index, d.Kind.Kind, d.Message)
+	if len(d.Deprecated) > 0 {
+		s += fmt.Sprintf("; deprecated in %s", JoinReleases(d.Deprecated))
+	}
+	if len(d.Removed) > 0 {
+		s += fmt.Sprintf("; removed in %s", JoinReleases(d.Removed))
+	}
+	return s
+}
+
+// JoinReleases lists releases, such as 1.29, 1.30.
+func JoinReleases(releases []*version.Version) string {
+	s := make([]string, len(releases))
+	for i, r := range releases {
+		s[i] = r.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// Deprecations returns the uses of deprecated or removed APIs, fields and
+// annotations by the documents of a checked file, in any of releases. The
+// lifecycle of the API versions is read from the scheme. Deprecated fields
+// and annotations are those the API server of the release the checker
+// validates against warns about, they are reported for the releases from
+// that one.
+func (c *Checker) Deprecations(result FileResult, releases []*version.Version) []Deprecation {
+	var deprecations []Deprecation
+	for _, doc := range result.Documents {
+		apiMessage := ""
+		if obj, err := newExternalObject(doc.Kind); err == nil {
+			apiMessage = deprecation.WarningMessage(obj)
+			d := Deprecation{Path: result.Path, Index: doc.Index, Kind: doc.Kind, Message: apiMessage}
+			for _, r := range releases {
+				if err := notServedError(doc.Kind, r); err != nil {
+					if d.Message == "" {
+						d.Message = err.Error()
+					}
+					d.Removed = append(d.Removed, r)
+				} else if deprecation.IsDeprecated(obj, int(r.Major()), int(r.Minor())) {
+					d.Deprecated = append(d.Deprecated, r)
+				}
+			}
+			if len(d.Deprecated) > 0 || len(d.Removed) > 0 {
+				deprecations = append(deprecations, d)
+			}
+		}
+
+		for _, w := range doc.Warnings {
+			if w.Message == apiMessage || !strings.Contains(strings.ToLower(w.Message), "deprecated") {
+				continue
+			}
+			d := Deprecation{Path: result.Path, Index: doc.Index, Kind: doc.Kind, Message: w.String()}
+			for _, r := range releases {
+				if !r.LessThan(c.KubernetesVersion) {
+					d.Deprecated = append(d.Deprecated, r)
+				}
+			}
+			if len(d.Deprecated) > 0 {
+				deprecations = append(deprecations, d)
+			}
+		}
+	}
+	return deprecations
+}
+
+// WebsiteRoot returns the root directory of the website the examples
+// directory of a locale, examplesDir, belongs to.
+func WebsiteRoot(examplesDir string) (string, error) {
+	abs, err := filepath.Abs(examplesDir)
+	if err != nil {
+		return "", err
+	}
+	// examplesDir is content/<locale>/examples
+	return filepath.Join(abs, "..", "..", ".."), nil
+}
+
+// ReleasesDir returns the data/releases directory of the website the
+// examples directory of a locale, examplesDir, belongs to.
+func ReleasesDir(examplesDir string) (string, error) {
+	root, err := WebsiteRoot(examplesDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "data", "releases"), nil
+}
+
+// WriteDeprecations writes a line per deprecation to w.
+func WriteDeprecations(w io.Writer, deprecations []Deprecation) error {
+	for _, d := range deprecations {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package examples decodes and validates the example manifests bundled with
+// the website under content/<lang>/examples.
+//
+// Every YAML and JSON file of an examples directory is split into documents,
+// each of which is decoded according to its apiVersion and kind and validated
+// the way the API server would validate it. Expectations for the files of a
+// directory are read from an optional examples.yaml file next to them.
+package examples
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package examplestest runs the validation of the examples of the website
+// as part of go test. Its flags select the Kubernetes release and the
+// reports, they are registered by the test binaries importing it.
+package examplestest
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/pod-security-admission/api"
+	"k8s.io/website/pkg/examples"
+	"k8s.io/website/pkg/examples/report"
+)
+
+var (
+	kubernetesVersion = flag.String("kubernetes-version", "", "Kubernetes release to validate the examples against, defaults to the release matching k8s.io/apimachinery")
+	failOnWarnings    = flag.Bool("fail-on-warnings", false, "Fail the examples raising warnings their manifest does not expect, such as the deprecation warnings of the API server")
+	failOnReferences  = flag.Bool("fail-on-dangling-references", false, "Fail the examples referring to objects that are neither among the examples of their directory nor declared as externals")
+	reportPath        = flag.String("report", "", "Write a report of the outcome of checking every example file to this file")
+	reportFormat      = flag.String("report-format", string(report.JSON), "Format of the -report file: json, junit or sarif")
+	podSecurityReport = flag.String("pod-security-report", "", "Write the strictest level of the Pod Security Standards each pod and pod template of the examples satisfies to this file")
+	deprecationReport = flag.String("deprecation-report", "", "Write the uses of APIs, fields and annotations deprecated or removed in a supported Kubernetes release, per data/releases, to this file")
+	update            = flag.Bool("update", false, "Write the golden files of the examples admitted against the objects of their context instead of comparing them")
+	changedSince      = flag.String("changed-since", "", "Only check the example files changed since this git revision, such as origin/main, and the files referring to them")
+	roundTrip         = flag.Bool("round-trip", false, "Convert the examples to every served version of their kind and back, warning about lossy conversions")
+)
+
+// Test checks every example file under dir as part of t, failing it for
+// each error found. Each directory and each of its files is a subtest of t,
+// named by their path relative to dir, such as pods/probe/exec-liveness.yaml,
+// and run in parallel. With -changed-since, only the files changed since the
+// given git revision and the files referring to them are checked, one after
+// the other.
+func Test(t *testing.T, dir string) {
+	kubeVersion, err := examples.TargetVersion(*kubernetesVersion)
+	if err != nil {
+		t.Fatalf("%v, use -kubernetes-version", err)
+	}
+	t.Logf("Validating examples in %s against Kubernetes %s\n", dir, kubeVersion)
+	format := report.Format(*reportFormat)
+	if !slices.Contains(report.Formats, format) {
+		t.Fatalf("unknown -report-format %q, expected one of %v", format, report.Formats)
+	}
+
+	var releases []*version.Version
+	if *deprecationReport != "" {
+		releasesDir, err := examples.ReleasesDir(dir)
+		if err == nil {
+			releases, err = examples.SupportedReleases(releasesDir)
+		}
+		if err != nil {
+			t.Fatalf("unable to read the supported releases: %v", err)
+		}
+	}
+
+	checker := examples.NewChecker(dir, kubeVersion)
+	checker.FailOnWarnings = *failOnWarnings
+	checker.FailOnDanglingReferences = *failOnReferences
+	checker.RoundTrip = *roundTrip
+	checker.UpdateGolden = *update
+
+	// The subtests report the outcome of their file here, for the summary
+	// to be logged once they are all done.
+	var (
+		lock              sync.Mutex
+		skipped           []string
+		deprecations      []examples.Deprecation
+		checked           []examples.FileResult
+		podSecurityLevels = map[api.Level]int{}
+		warnings          int
+	)
+	check := func(t *testing.T, r examples.FileResult) {
+		t.Logf("Checking file %s\n", r.Path)
+		var fileDeprecations []examples.Deprecation
+		if releases != nil && r.Skipped == "" {
+			fileDeprecations = checker.Deprecations(r, releases)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if *podSecurityReport != "" || *reportPath != "" {
+			checked = append(checked, r)
+		}
+		deprecations = append(deprecations, fileDeprecations...)
+		if r.Skipped != "" {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", r.Path, r.Skipped))
+			t.Skip(r.Skipped)
+		}
+		for _, err := range r.Errors {
+			t.Errorf("%s: %v", r.Path, err)
+		}
+		for _, doc := range r.Documents {
+			for _, err := range doc.Errors {
+				t.Errorf("%s: document %d (%s): %v", errorPosition(r.Path, err), doc.Index, doc.Kind.Kind, err)
+			}
+			for _, err := range doc.Rejections {
+				t.Errorf("%s: document %d (%s): rejected: %v", r.Path, doc.Index, doc.Kind.Kind, err)
+			}
+			if doc.PodSecurity != nil {
+				podSecurityLevels[doc.PodSecurity.Level]++
+				t.Logf("%s: document %d (%s): pod security: %s\n", r.Path, doc.Index, doc.Kind.Kind, doc.PodSecurity)
+			}
+			for _, err := range doc.ExpectedErrors {
+				t.Logf("%s: document %d (%s): expected error: %v\n", r.Path, doc.Index, doc.Kind.Kind, err)
+			}
+			for _, w := range doc.Warnings {
+				if !w.Expected {
+					warnings++
+					position := r.Path
+					if w.Location != nil {
+						position = w.Location.String()
+					}
+					t.Logf("%s: document %d (%s): warning: %s\n", position, doc.Index, doc.Kind.Kind, w)
+				}
+			}
+		}
+		for _, a := range r.Admission {
+			t.Logf("%s: %s\n", r.Path, a)
+		}
+	}
+
+	t.Cleanup(func() {
+		// subtests end in any order
+		slices.SortFunc(checked, func(a, b examples.FileResult) int { return strings.Compare(a.Path, b.Path) })
+		slices.SortStableFunc(deprecations, func(a, b examples.Deprecation) int { return strings.Compare(a.Path, b.Path) })
+		slices.Sort(skipped)
+
+		if *deprecationReport != "" {
+			if err := writeDeprecationReport(*deprecationReport, deprecations); err != nil {
+				t.Errorf("unable to write the deprecation report: %v", err)
+			}
+			t.Logf("Found %d uses of APIs, fields and annotations deprecated or removed in Kubernetes %s", len(deprecations), examples.JoinReleases(releases))
+		}
+		if *reportPath != "" {
+			if err := writeReport(*reportPath, format, examples.ReportRun(dir, kubeVersion, checked)); err != nil {
+				t.Errorf("unable to write the report: %v", err)
+			}
+		}
+		if *podSecurityReport != "" {
+			if err := writePodSecurityReport(*podSecurityReport, checked); err != nil {
+				t.Errorf("unable to write the pod security report: %v", err)
+			}
+		}
+		t.Logf("Found %d pods and pod templates satisfying the restricted Pod Security Standard, %d the baseline one and %d only the privileged one",
+			podSecurityLevels[api.LevelRestricted], podSecurityLevels[api.LevelBaseline], podSecurityLevels[api.LevelPrivileged])
+		if warnings > 0 {
+			t.Logf("Found %d unexpected warnings, use -fail-on-warnings and -fail-on-dangling-references to fail on them", warnings)
+		}
+		if len(skipped) > 0 {
+			t.Logf("Found %d example files that were not validated:\n  %s", len(skipped), strings.Join(skipped, "\n  "))
+		}
+	})
+
+	if *changedSince != "" {
+		n, err := checker.CheckChanged(*changedSince, func(r examples.FileResult) {
+			t.Run(subtestName(dir, r.Path), func(t *testing.T) {
+				check(t, r)
+			})
+		})
+		if err != nil {
+			t.Errorf("Expected no error, Got %v", err)
+		}
+		t.Logf("Checked %d example files changed since %s or referring to changed files", n, *changedSince)
+		return
+	}
+
+	dirs, err := checker.Directories()
+	if err != nil {
+		t.Errorf("Expected no error, Got %v", err)
+	}
+	for _, d := range dirs {
+		t.Run(subtestName(dir, d.Path), func(t *testing.T) {
+			t.Parallel()
+			for i, path := range d.Files {
+				t.Run(filepath.Base(path), func(t *testing.T) {
+					t.Parallel()
+					check(t, d.CheckFile(i))
+				})
+			}
+		})
+	}
+}
+
+// subtestName returns the name of the subtest of a file or directory under
+// root: its path relative to root, with forward slashes.
+func subtestName(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// errorPosition returns the position in its file of the field an error of a
+// document is about, or the path of the file when it is not known.
+func errorPosition(path string, err error) string {
+	if location, ok := examples.ErrorLocation(err); ok {
+		return location.String()
+	}
+	return path
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examplestest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"k8s.io/website/pkg/examples"
+)
+
+// TestLocales checks the examples of every locale, including the ones
+// without a test of their own.
+func TestLocales(t *testing.T) {
+	locales, err := examples.Locales(filepath.Join("..", "..", "..", "content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locales) == 0 {
+		t.Fatal("no examples directory found")
+	}
+	for _, dir := range locales {
+		t.Run(filepath.Base(filepath.Dir(dir)), func(t *testing.T) {
+			Test(t, dir)
+		})
+	}
+}
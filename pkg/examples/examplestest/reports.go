@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examplestest
+
+import (
+	"bytes"
+	"os"
+	"sync"
+
+	"k8s.io/website/pkg/examples"
+	"k8s.io/website/pkg/examples/report"
+)
+
+// truncatedReports holds the paths of the reports written by the test
+// binary: the first write to a report replaces its previous content, those
+// of the following locales are appended to it.
+var (
+	truncatedReports     = map[string]bool{}
+	truncatedReportsLock sync.Mutex
+)
+
+// openReport opens the report at path for writing.
+func openReport(path string) (*os.File, error) {
+	truncatedReportsLock.Lock()
+	defer truncatedReportsLock.Unlock()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !truncatedReports[path] {
+		flags |= os.O_TRUNC
+		truncatedReports[path] = true
+	}
+	return os.OpenFile(path, flags, 0644)
+}
+
+// writeDeprecationReport writes a line per deprecation to the file at path.
+func writeDeprecationReport(path string, deprecations []examples.Deprecation) error {
+	f, err := openReport(path)
+	if err != nil {
+		return err
+	}
+	if err := examples.WriteDeprecations(f, deprecations); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writePodSecurityReport writes a line per pod or pod template of the
+// checked files to the file at path, with the strictest level of the Pod
+// Security Standards it satisfies.
+func writePodSecurityReport(path string, results []examples.FileResult) error {
+	f, err := openReport(path)
+	if err != nil {
+		return err
+	}
+	if err := examples.WritePodSecurity(f, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reportRuns holds the runs of the report written by the test binary, one
+// per examples directory checked, such as those of the locales.
+var (
+	reportRuns     []report.Run
+	reportRunsLock sync.Mutex
+)
+
+// writeReport adds a run to the report of the test binary and writes the
+// whole report to the file at path in the given format.
+func writeReport(path string, format report.Format, run report.Run) error {
+	reportRunsLock.Lock()
+	defer reportRunsLock.Unlock()
+	reportRuns = append(reportRuns, run)
+	r := &report.Report{Runs: reportRuns}
+	var buf bytes.Buffer
+	if err := r.Write(&buf, format); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
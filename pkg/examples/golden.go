@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// GoldenDir is the name of the directories holding the golden files of the
+// examples next to them. The examples walk skips them.
+const GoldenDir = "testdata"
+
+// admissionOutcome is the outcome of admitting a document in the namespace
+// of the context of its file, as recorded in a golden file: the resources a
+// Pod or PersistentVolumeClaim is created with, once defaulted by admission
+// plugins, or the reasons it is rejected for.
+type admissionOutcome struct {
+	Rejected       []string             `json:"rejected,omitempty"`
+	InitContainers []containerResources `json:"initContainers,omitempty"`
+	Containers     []containerResources `json:"containers,omitempty"`
+	// Resources requested by a PersistentVolumeClaim.
+	Resources *corev1.VolumeResourceRequirements `json:"resources,omitempty"`
+}
+
+type containerResources struct {
+	Name      string                      `json:"name"`
+	Resources corev1.ResourceRequirements `json:"resources"`
+}
+
+// admissionOutcomes returns the content of the golden file of an example
+// file whose documents are valid: the JSON of the outcome of each document.
+func admissionOutcomes(result FileResult) ([]byte, error) {
+	outcomes := make([]admissionOutcome, len(result.Documents))
+	for i, doc := range result.Documents {
+		for _, err := range doc.Rejections {
+			outcomes[i].Rejected = append(outcomes[i].Rejected, err.Error())
+		}
+		if doc.Object == nil {
+			continue
+		}
+		switch obj := doc.Object.(type) {
+		case *api.Pod:
+			pod := &corev1.Pod{}
+			if err := legacyscheme.Scheme.Convert(obj, pod, nil); err != nil {
+				return nil, err
+			}
+			for _, c := range pod.Spec.InitContainers {
+				outcomes[i].InitContainers = append(outcomes[i].InitContainers, containerResources{Name: c.Name, Resources: c.Resources})
+			}
+			for _, c := range pod.Spec.Containers {
+				outcomes[i].Containers = append(outcomes[i].Containers, containerResources{Name: c.Name, Resources: c.Resources})
+			}
+		case *api.PersistentVolumeClaim:
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := legacyscheme.Scheme.Convert(obj, pvc, nil); err != nil {
+				return nil, err
+			}
+			outcomes[i].Resources = &pvc.Spec.Resources
+		}
+	}
+	data, err := json.MarshalIndent(outcomes, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// checkGolden compares the admission outcomes of a file with its golden file,
+// in the GoldenDir next to it, or writes the golden file when update is set.
+// Localized examples are compared with the golden files of the English
+// ones when they have none, which update does not write.
+func (c *Checker) checkGolden(result *FileResult, update bool) {
+	for _, doc := range result.Documents {
+		if len(doc.Errors) > 0 {
+			// invalid documents have no outcome
+			return
+		}
+	}
+	got, err := admissionOutcomes(*result)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("unable to record the admission outcome: %v", err))
+		return
+	}
+
+	dir := filepath.Dir(result.Path)
+	name := strings.TrimSuffix(filepath.Base(result.Path), filepath.Ext(result.Path)) + ".golden"
+	path := c.resolvePath(dir, filepath.Join(GoldenDir, name))
+	if update && (c.FallbackRoot == "" || path == filepath.Join(dir, GoldenDir, name)) {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, got, 0644)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		result.Errors = append(result.Errors, fmt.Errorf("no golden file %s, use -update to write it", path))
+		return
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		result.Errors = append(result.Errors, fmt.Errorf("admission outcome does not match %s, use -update to write it:\n%s", path, got))
+	}
+}
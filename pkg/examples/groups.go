@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	apiextensionsinstall "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	apiserverinstall "k8s.io/apiserver/pkg/apis/apiserver/install"
+	"k8s.io/apiserver/pkg/apis/audit"
+	auditinstall "k8s.io/apiserver/pkg/apis/audit/install"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/authentication"
+	"k8s.io/kubernetes/pkg/apis/authorization"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/policy"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	schedulerscheme "k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+
+	// initialize install packages
+	_ "k8s.io/kubernetes/pkg/apis/admissionregistration/install"
+	_ "k8s.io/kubernetes/pkg/apis/apiserverinternal/install"
+	_ "k8s.io/kubernetes/pkg/apis/apps/install"
+	_ "k8s.io/kubernetes/pkg/apis/authentication/install"
+	_ "k8s.io/kubernetes/pkg/apis/authorization/install"
+	_ "k8s.io/kubernetes/pkg/apis/autoscaling/install"
+	_ "k8s.io/kubernetes/pkg/apis/batch/install"
+	_ "k8s.io/kubernetes/pkg/apis/certificates/install"
+	_ "k8s.io/kubernetes/pkg/apis/coordination/install"
+	_ "k8s.io/kubernetes/pkg/apis/core/install"
+	_ "k8s.io/kubernetes/pkg/apis/discovery/install"
+	_ "k8s.io/kubernetes/pkg/apis/events/install"
+	_ "k8s.io/kubernetes/pkg/apis/flowcontrol/install"
+	_ "k8s.io/kubernetes/pkg/apis/networking/install"
+	_ "k8s.io/kubernetes/pkg/apis/node/install"
+	_ "k8s.io/kubernetes/pkg/apis/policy/install"
+	_ "k8s.io/kubernetes/pkg/apis/rbac/install"
+	_ "k8s.io/kubernetes/pkg/apis/resource/install"
+	_ "k8s.io/kubernetes/pkg/apis/scheduling/install"
+	_ "k8s.io/kubernetes/pkg/apis/storage/install"
+	_ "k8s.io/kubernetes/pkg/apis/storagemigration/install"
+)
+
+func init() {
+	// CustomResourceDefinitions are served by the apiextensions-apiserver
+	// and are not part of the legacy scheme.
+	apiextensionsinstall.Install(legacyscheme.Scheme)
+	// Configuration files of the control plane components are not served
+	// either, they have schemes of their own.
+	apiserverinstall.Install(legacyscheme.Scheme)
+	auditinstall.Install(legacyscheme.Scheme)
+	schedulerscheme.AddToScheme(legacyscheme.Scheme)
+}
+
+// unvalidatedGroups holds the reason for not validating the objects of the
+// API groups installed in the scheme without a validation of their own,
+// keyed by group name. Their documents are validated against their OpenAPI
+// schema, if any.
+var unvalidatedGroups = map[string]string{
+	"abac.authorization.kubernetes.io": "policy file of the ABAC authorizer, not served",
+	"admission.k8s.io":                 "payload of admission webhooks, not served",
+	"extensions":                       "no longer served, its kinds moved to other groups",
+	"imagepolicy.k8s.io":               "payload of the ImagePolicyWebhook, not served",
+	// The API server authenticates the tokens of a TokenReview without
+	// validating the review.
+	authentication.GroupName: "reviews are not validated by the API server",
+}
+
+// unvalidatedKinds holds the reason for not validating the objects of the
+// kinds of the other API groups without a validation of their own, keyed by
+// group and kind. Their documents are validated against their OpenAPI
+// schema, if any.
+var unvalidatedKinds = map[schema.GroupKind]string{
+	api.Kind("Binding"):                          "subresource of pods, created by the schedulers",
+	api.Kind("ComponentStatus"):                  "read-only, reported by the API server",
+	api.Kind("NodeProxyOptions"):                 "options of the proxy subresource of nodes",
+	api.Kind("PodAttachOptions"):                 "options of the attach subresource of pods",
+	api.Kind("PodExecOptions"):                   "options of the exec subresource of pods",
+	api.Kind("PodLogOptions"):                    "options of the log subresource of pods",
+	api.Kind("PodPortForwardOptions"):            "options of the portforward subresource of pods",
+	api.Kind("PodProxyOptions"):                  "options of the proxy subresource of pods",
+	api.Kind("PodStatusResult"):                  "status of a pod reported by the kubelet, not served",
+	api.Kind("RangeAllocation"):                  "allocation state of the API server, not served",
+	api.Kind("SerializedReference"):              "annotation of the objects created by controllers, not served",
+	api.Kind("ServiceProxyOptions"):              "options of the proxy subresource of services",
+	apps.Kind("DeploymentRollback"):              "no longer served, deployments are rolled back by kubectl",
+	apps.Kind("Scale"):                           "scale subresource, validated along with its object",
+	authorization.Kind("SelfSubjectRulesReview"): "reviews of the rules of the user are not validated by the API server",
+	autoscaling.Kind("Scale"):                    "scale subresource, validated along with its object",
+	policy.Kind("Eviction"):                      "eviction subresource of pods, not persisted",
+	// The configuration files of the API server are only validated by the
+	// API server when it loads them.
+	{Group: apiserver.LegacyGroupName, Kind: "AdmissionConfiguration"}: "configuration file of the API server without a validation of its own",
+	{Group: apiserver.GroupName, Kind: "AdmissionConfiguration"}:       "configuration file of the API server without a validation of its own",
+	{Group: apiserver.GroupName, Kind: "AuthenticationConfiguration"}:  "configuration file of the API server, validated against the flags of the API server",
+	{Group: apiserver.GroupName, Kind: "AuthorizationConfiguration"}:   "configuration file of the API server, validated against the flags of the API server",
+	{Group: apiserver.GroupName, Kind: "EncryptionConfiguration"}:      "configuration file of the API server, validated along with its key material",
+	{Group: apiserver.GroupName, Kind: "TracingConfiguration"}:         "configuration file of the API server, validated against its feature gates",
+	{Group: audit.GroupName, Kind: "Event"}:                            "payload of the audit backends, not served",
+	// The arguments of the scheduler plugins are validated along with the
+	// KubeSchedulerConfiguration holding them.
+	{Group: schedulerconfig.GroupName, Kind: "DefaultPreemptionArgs"}:               "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "InterPodAffinityArgs"}:                "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "NodeAffinityArgs"}:                    "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "NodeResourcesBalancedAllocationArgs"}: "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "NodeResourcesFitArgs"}:                "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "PodTopologySpreadArgs"}:               "arguments of a scheduler plugin",
+	{Group: schedulerconfig.GroupName, Kind: "VolumeBindingArgs"}:                   "arguments of a scheduler plugin",
+}
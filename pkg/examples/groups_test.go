@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+)
+
+// TestGroups checks that the objects of every kind installed in the scheme
+// the examples are decoded with are validated, unless its group or the kind
+// itself is listed with the reason it is not.
+func TestGroups(t *testing.T) {
+	installed := map[schema.GroupKind]bool{}
+	for gvk, typ := range legacyscheme.Scheme.AllKnownTypes() {
+		// the kinds of the meta API, such as WatchEvent, belong to every
+		// group and are not examples
+		if gvk.Version != runtime.APIVersionInternal || strings.HasPrefix(typ.PkgPath(), "k8s.io/apimachinery/") {
+			continue
+		}
+		if _, ok := unvalidatedGroups[gvk.Group]; ok {
+			continue
+		}
+		// the items of lists are validated in turn
+		if obj, ok := reflect.New(typ).Interface().(runtime.Object); !ok || meta.IsListType(obj) {
+			continue
+		}
+		installed[gvk.GroupKind()] = true
+	}
+	if len(installed) == 0 {
+		t.Fatal("no kind installed in the scheme")
+	}
+
+	for gk := range installed {
+		_, hasStrategy := strategies[gk]
+		_, hasValidator := validators[gk]
+		_, unvalidated := unvalidatedKinds[gk]
+		switch {
+		case !hasStrategy && !hasValidator && !unvalidated:
+			t.Errorf("kind %s is installed but has neither a strategy nor a validator", gk)
+		case (hasStrategy || hasValidator) && unvalidated:
+			t.Errorf("kind %s is validated but listed as not validated", gk)
+		}
+	}
+	for gk, reason := range unvalidatedKinds {
+		if !installed[gk] {
+			t.Errorf("kind %s is listed as not validated but is not installed", gk)
+		}
+		if reason == "" {
+			t.Errorf("kind %s is not validated without a reason", gk)
+		}
+	}
+	for group, reason := range unvalidatedGroups {
+		if reason == "" {
+			t.Errorf("group %q is not validated without a reason", group)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const testConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+
+const testPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+`
+
+// decodeTestDocument converts a YAML document into JSON, the way the
+// documents of example files are read, along with its apiVersion and kind.
+func decodeTestDocument(t *testing.T, source string) ([]byte, schema.GroupVersionKind) {
+	t.Helper()
+	data, err := yaml.ToJSON([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gvk, err := documentKind(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, gvk
+}
+
+// decodeTestObject decodes a YAML document into an internal object, along
+// with the apiVersion and kind it declares.
+func decodeTestObject(t *testing.T, source string) (runtime.Object, schema.GroupVersionKind) {
+	t.Helper()
+	data, gvk := decodeTestDocument(t, source)
+	obj, err := decodeObject(data, gvk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obj, gvk
+}
+
+// errorFields returns the sorted fields of errs.
+func errorFields(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	slices.Sort(fields)
+	return fields
+}
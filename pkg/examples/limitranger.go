@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/plugin/pkg/admission/limitranger"
+)
+
+// limitRanges returns the LimitRanges among the internal objects of the
+// context of a file, converted to the version the LimitRanger admission
+// plugin reads them in.
+func limitRanges(context []runtime.Object) ([]*corev1.LimitRange, error) {
+	var ranges []*corev1.LimitRange
+	for _, obj := range context {
+		internal, ok := obj.(*api.LimitRange)
+		if !ok {
+			continue
+		}
+		limitRange := &corev1.LimitRange{}
+		if err := legacyscheme.Scheme.Convert(internal, limitRange, nil); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, limitRange)
+	}
+	return ranges, nil
+}
+
+// limitRangerResource returns the resource of an internal object the
+// LimitRanger admission plugin applies limits to, or an empty string. The
+// plugin tells the actions which limits to apply by the resource of the
+// request, not by the kind of the object.
+func limitRangerResource(obj runtime.Object) string {
+	switch obj.(type) {
+	case *api.Pod:
+		return "pods"
+	case *api.PersistentVolumeClaim:
+		return "persistentvolumeclaims"
+	}
+	return ""
+}
+
+// limitRangerMutate sets the default resources of an internal object from
+// ranges, as the LimitRanger admission plugin does before the object is
+// validated.
+func limitRangerMutate(obj runtime.Object, ranges []*corev1.LimitRange) []error {
+	resource := limitRangerResource(obj)
+	if resource == "" {
+		return nil
+	}
+	actions := &limitranger.DefaultLimitRangerActions{}
+	var errs []error
+	for _, limitRange := range ranges {
+		if !actions.SupportsLimit(limitRange) {
+			continue
+		}
+		if err := actions.MutateLimit(limitRange, resource, obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// limitRangerValidate checks the resources of a valid internal object
+// against the limits of ranges, as the LimitRanger admission plugin does
+// before the object is persisted.
+func limitRangerValidate(obj runtime.Object, ranges []*corev1.LimitRange) []error {
+	resource := limitRangerResource(obj)
+	if resource == "" {
+		return nil
+	}
+	actions := &limitranger.DefaultLimitRangerActions{}
+	var errs []error
+	for _, limitRange := range ranges {
+		if !actions.SupportsLimit(limitRange) {
+			continue
+		}
+		if err := actions.ValidateLimit(limitRange, resource, obj); err != nil {
+			errs = append(errs, flattenErrors(err)...)
+		}
+	}
+	return errs
+}
+
+// flattenErrors returns the errors of an aggregate, whose order follows that
+// of the iteration over a map, sorted by message.
+func flattenErrors(err error) []error {
+	agg, ok := err.(utilerrors.Aggregate)
+	if !ok {
+		return []error{err}
+	}
+	errs := utilerrors.Flatten(agg).Errors()
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+const cpuLimitRange = `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: cpu
+spec:
+  limits:
+  - type: Container
+    default:
+      cpu: 500m
+    max:
+      cpu: "1"
+`
+
+const storageLimitRange = `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: storage
+spec:
+  limits:
+  - type: PersistentVolumeClaim
+    max:
+      storage: 2Gi
+`
+
+const largeClaim = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: large
+spec:
+  accessModes: [ReadWriteOnce]
+  resources:
+    requests:
+      storage: 5Gi
+`
+
+const largePod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: large
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+    resources:
+      limits:
+        cpu: "2"
+`
+
+func TestLimitRanger(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		source    string
+		context   []string
+		wantLimit string
+		wantErr   string
+	}{{
+		name:      "defaulted",
+		source:    testPod,
+		context:   []string{cpuLimitRange},
+		wantLimit: "500m",
+	}, {
+		name:      "exceeded maximum",
+		source:    largePod,
+		context:   []string{cpuLimitRange},
+		wantLimit: "2",
+		wantErr:   "maximum cpu usage per Container is 1, but limit is 2",
+	}, {
+		name:    "exceeded maximum of a claim",
+		source:  largeClaim,
+		context: []string{storageLimitRange},
+		wantErr: "maximum storage usage per PersistentVolumeClaim is 2Gi, but request is 5Gi",
+	}, {
+		name:      "without limit range",
+		source:    largePod,
+		wantLimit: "2",
+	}, {
+		name:    "unlimited kind",
+		source:  testConfigMap,
+		context: []string{cpuLimitRange},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var context []runtime.Object
+			for _, source := range tc.context {
+				obj, _ := decodeTestObject(t, source)
+				context = append(context, obj)
+			}
+			ranges, err := limitRanges(context)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj, _ := decodeTestObject(t, tc.source)
+			if errs := limitRangerMutate(obj, ranges); len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if pod, ok := obj.(*api.Pod); ok {
+				limit := pod.Spec.Containers[0].Resources.Limits[api.ResourceCPU]
+				if limit.String() != tc.wantLimit {
+					t.Errorf("expected a cpu limit of %s, got %s", tc.wantLimit, limit.String())
+				}
+			}
+
+			errs := limitRangerValidate(obj, ranges)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("expected no error, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantErr) {
+				t.Errorf("expected %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Location is the position of a field of a document in an example file.
+type Location struct {
+	// Path of the file.
+	Path string
+	// Document is the index of the document in the file.
+	Document int
+	// Line and Column of the field, counted from 1.
+	Line   int
+	Column int
+}
+
+// String formats the location the way compilers do, for editors and code
+// review tools to link to it.
+func (l Location) String() string {
+	return fmt.Sprintf("%s:%d:%d", l.Path, l.Line, l.Column)
+}
+
+// LocatedError is an error about a field of a document, along with the
+// position of the field in its file.
+type LocatedError struct {
+	Err      error
+	Location Location
+}
+
+func (e *LocatedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LocatedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorLocation returns the position of the field an error of a document is
+// about in its file, if known.
+func ErrorLocation(err error) (Location, bool) {
+	var located *LocatedError
+	if errors.As(err, &located) {
+		return located.Location, true
+	}
+	return Location{}, false
+}
+
+// documentStartLines returns the line of a YAML stream, counted from 1, that
+// each document a yaml.YAMLReader splits it in starts at.
+func documentStartLines(data []byte) []int {
+	var starts []int
+	inDocument := false
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		// a separator ends the current document, if any, otherwise the
+		// reader keeps it as the first line of the next one
+		if bytes.HasPrefix(line, []byte("---")) && inDocument {
+			inDocument = false
+			continue
+		}
+		if !inDocument {
+			starts = append(starts, i+1)
+			inDocument = true
+		}
+	}
+	return starts
+}
+
+// parseSourceNode parses the YAML or JSON source of a document for the
+// position of its fields, or returns nil.
+func parseSourceNode(source []byte) *yaml.Node {
+	var node yaml.Node
+	if err := yaml.Unmarshal(source, &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+	return node.Content[0]
+}
+
+// strictFieldPattern matches the field of a strict decoding error.
+var strictFieldPattern = regexp.MustCompile(`(?:unknown|duplicate) field "([^"]+)"`)
+
+// errorField returns the path of the field an error is about, if known.
+func errorField(err error) string {
+	var fieldErr *field.Error
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Field
+	}
+	if m := strictFieldPattern.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// locate returns the position of a field of the document, such as
+// spec.containers[0].image, in the file at path. The position of the deepest
+// ancestor of a missing field found in the source of the document is
+// returned for it.
+func (d *DocumentResult) locate(path, fieldPath string) (Location, bool) {
+	if d.source == nil || fieldPath == "" {
+		return Location{}, false
+	}
+	node, at := d.source, d.source
+	for _, segment := range splitFieldPath(fieldPath) {
+		key, value := childNode(node, segment)
+		if value == nil {
+			break
+		}
+		node, at = value, key
+	}
+	return Location{Path: path, Document: d.Index, Line: d.line + at.Line - 1, Column: at.Column}, true
+}
+
+// locateErrors sets the position in their file of the fields the errors and
+// warnings of the documents of a checked file are about, when they are found
+// in the source of the documents.
+func locateErrors(result *FileResult) {
+	for i := range result.Documents {
+		doc := &result.Documents[i]
+		for j, err := range doc.Errors {
+			if _, ok := ErrorLocation(err); ok {
+				continue
+			}
+			if location, ok := doc.locate(result.Path, errorField(err)); ok {
+				doc.Errors[j] = &LocatedError{Err: err, Location: location}
+			}
+		}
+		for j := range doc.Warnings {
+			w := &doc.Warnings[j]
+			if w.Location != nil {
+				continue
+			}
+			if location, ok := doc.locate(result.Path, w.FieldRef); ok {
+				w.Location = &location
+			}
+		}
+	}
+}
+
+// splitFieldPath splits a field path, such as metadata.labels[app] or
+// spec.containers[0].image, in the names of its fields and its subscripts.
+func splitFieldPath(path string) []string {
+	var segments []string
+	for len(path) > 0 {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return append(segments, path)
+			}
+			segments = append(segments, path[:end+1])
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			segments = append(segments, path[:end])
+			path = path[end:]
+		}
+	}
+	return segments
+}
+
+// childNode returns the key and value nodes of the field of node a segment
+// of a field path names. The key of an item of a sequence is the item
+// itself. Both are nil when node has no such field.
+func childNode(node *yaml.Node, segment string) (key, value *yaml.Node) {
+	name := segment
+	if strings.HasPrefix(segment, "[") {
+		name = strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")
+		if node.Kind == yaml.SequenceNode {
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return nil, nil
+			}
+			return node.Content[i], node.Content[i]
+		}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"testing"
+)
+
+const multiDocument = `# leading comment
+apiVersion: v1
+kind: ConfigMap
+---
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    app: web
+spec:
+  containers:
+  - name: web
+    image: nginx
+`
+
+func TestLocate(t *testing.T) {
+	_, sources, lines, err := splitYAML([]byte(multiDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(sources))
+	}
+
+	for _, tc := range []struct {
+		document     int
+		field        string
+		line, column int
+	}{
+		{document: 0, field: "kind", line: 3, column: 1},
+		// a missing field is located at its parent
+		{document: 0, field: "data[key]", line: 2, column: 1},
+		{document: 1, field: "metadata.labels[app]", line: 10, column: 5},
+		{document: 1, field: "spec.containers[0].image", line: 14, column: 5},
+		{document: 1, field: "spec.containers[0].resources.limits", line: 13, column: 5},
+		{document: 1, field: "spec.containers[1].image", line: 12, column: 3},
+	} {
+		doc := DocumentResult{Index: tc.document, source: parseSourceNode(sources[tc.document]), line: lines[tc.document]}
+		location, ok := doc.locate("example.yaml", tc.field)
+		if !ok {
+			t.Errorf("%s: not located", tc.field)
+			continue
+		}
+		if location.Line != tc.line || location.Column != tc.column || location.Document != tc.document {
+			t.Errorf("%s: expected document %d at %d:%d, got %d at %d:%d", tc.field, tc.document, tc.line, tc.column, location.Document, location.Line, location.Column)
+		}
+	}
+}
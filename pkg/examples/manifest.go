@@ -0,0 +1,382 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/pod-security-admission/api"
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestFile is the name of the file holding the expectations for the
+// examples in its directory.
+const ManifestFile = "examples.yaml"
+
+// Manifest is the content of a ManifestFile.
+type Manifest struct {
+	// Skip is the reason for not validating any file in the directory.
+	Skip string `json:"skip,omitempty"`
+	// Files holds the expectations for individual files, keyed by file name
+	// without extension. Files that are not listed are validated according
+	// to the apiVersion and kind of their documents.
+	Files map[string]FileExpectations `json:"files,omitempty"`
+	// Stubs declare custom resource kinds the examples refer to without a
+	// CustomResourceDefinition among them, such as the parameters of a
+	// policy.
+	Stubs []KindStub `json:"stubs,omitempty"`
+	// Externals declare the objects the examples refer to without creating
+	// them, such as a Secret a tutorial creates with kubectl.
+	Externals []ExternalObject `json:"externals,omitempty"`
+}
+
+// ExternalObject is an object the examples of a directory refer to that is
+// created otherwise, such as by a kubectl command of the page showing them.
+type ExternalObject struct {
+	// Kind of the object, such as Secret.
+	Kind string `json:"kind"`
+	// Name of the object.
+	Name string `json:"name,omitempty"`
+	// Namespace of the object. Objects of any namespace match when empty.
+	Namespace string `json:"namespace,omitempty"`
+	// Labels of the object, such as those of a Pod a Service selects.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// KindStub declares the schema of a custom resource kind.
+type KindStub struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Namespaced is set for kinds whose resources are namespaced.
+	Namespaced bool `json:"namespaced,omitempty"`
+	// Schema is the OpenAPI v3 schema of the resources of the kind.
+	Schema *apiextensionsv1.JSONSchemaProps `json:"schema"`
+}
+
+// ExpectedWarning is a warning expected when checking a file.
+type ExpectedWarning struct {
+	// FieldRef is the field the warning is about, such as the expression of
+	// a policy validation. Any field matches when empty.
+	FieldRef string `json:"fieldRef,omitempty"`
+	// Message is part of the text of the warning.
+	Message string `json:"message"`
+}
+
+// FileExpectations holds the expectations for a single example file.
+type FileExpectations struct {
+	// Kinds are the expected kinds of the documents in the file, in order.
+	Kinds []string `json:"kinds,omitempty"`
+	// Skip is the reason for not validating the file.
+	Skip string `json:"skip,omitempty"`
+	// FeatureGates are set while the file is validated.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// MinVersion and MaxVersion are the first and last Kubernetes releases
+	// the file applies to. The file is skipped for other releases.
+	MinVersion string `json:"minVersion,omitempty"`
+	MaxVersion string `json:"maxVersion,omitempty"`
+	// Warnings are expected when checking the file, such as the type
+	// checking warnings of a policy. Any other warning is reported, and is
+	// an error with -fail-on-warnings.
+	Warnings []ExpectedWarning `json:"warnings,omitempty"`
+	// Substitute holds values for the $VARIABLES of a template, which are
+	// replaced before the file is decoded.
+	Substitute map[string]string `json:"substitute,omitempty"`
+	// Admission holds the scenarios evaluating the ValidatingAdmissionPolicy
+	// of the file against example objects.
+	Admission []AdmissionScenario `json:"admission,omitempty"`
+	// Context lists the files holding the objects created before those of
+	// the file, in the same namespace, such as the LimitRanges they are
+	// admitted against. Files are relative to the directory of the manifest.
+	Context []string `json:"context,omitempty"`
+	// Expect is the expected failure of a document of the file. The
+	// documents are expected to be valid and admitted otherwise.
+	Expect *ExpectedFailure `json:"expect,omitempty"`
+	// PodSecurity is the strictest level of the Pod Security Standards,
+	// privileged, baseline or restricted, that all the pods and pod
+	// templates of the file satisfy.
+	PodSecurity string `json:"podSecurity,omitempty"`
+}
+
+const (
+	ExpectInvalid  = "invalid"
+	ExpectRejected = "rejected"
+)
+
+// ExpectedFailure is the failure expected when creating a document, such as
+// one a tutorial shows being rejected.
+type ExpectedFailure struct {
+	// Document is the index of the document in the file.
+	Document int `json:"document,omitempty"`
+	// Result is either "invalid", for a document failing validation, or
+	// "rejected", for a valid one denied by an admission plugin.
+	Result string `json:"result"`
+	// Errors are expected among those of the document.
+	Errors []ExpectedError `json:"errors,omitempty"`
+}
+
+// ExpectedError is an error expected when checking a document.
+type ExpectedError struct {
+	// Field is the path of the field the error is about, such as
+	// spec.containers[0].image. Any field matches when empty.
+	Field string `json:"field,omitempty"`
+	// Message is part of the text of the error.
+	Message string `json:"message,omitempty"`
+}
+
+// AdmissionScenario evaluates a ValidatingAdmissionPolicy, along with its
+// bindings and parameters, against requests creating example objects. Files
+// are named by their path relative to the directory of the manifest.
+type AdmissionScenario struct {
+	// Name describes the scenario.
+	Name string `json:"name"`
+	// Bindings are the files holding the bindings of the policy.
+	Bindings []string `json:"bindings,omitempty"`
+	// Params are the files holding the parameter resources of the policy.
+	Params []string `json:"params,omitempty"`
+	// Namespaces are those of the cluster, for bindings selecting
+	// namespaces by label.
+	Namespaces []NamespaceStub `json:"namespaces,omitempty"`
+	// Requests create example objects, which are expected to be admitted
+	// or denied.
+	Requests []AdmissionRequest `json:"requests"`
+}
+
+// NamespaceStub declares a namespace of an admission scenario.
+type NamespaceStub struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+const (
+	AdmissionAdmit = "admit"
+	AdmissionDeny  = "deny"
+)
+
+// AdmissionRequest is a request creating the objects of an example file.
+type AdmissionRequest struct {
+	// Object is the file holding the objects.
+	Object string `json:"object"`
+	// Namespace the objects are created in, defaults to their own.
+	Namespace string `json:"namespace,omitempty"`
+	// Expect is either "admit" or "deny".
+	Expect string `json:"expect"`
+	// Message is part of the message of a denial.
+	Message string `json:"message,omitempty"`
+}
+
+// Matches reports whether err is the expected error.
+func (e ExpectedError) Matches(err error) bool {
+	if e.Field != "" {
+		var fieldErr *field.Error
+		if !errors.As(err, &fieldErr) || fieldErr.Field != e.Field {
+			return false
+		}
+	}
+	return strings.Contains(err.Error(), e.Message)
+}
+
+// SkipReason returns why the file is not validated against Kubernetes
+// release v, or an empty string if it is.
+func (f FileExpectations) SkipReason(v *version.Version) (string, error) {
+	if f.Skip != "" {
+		return f.Skip, nil
+	}
+	if f.MinVersion != "" {
+		min, err := version.ParseGeneric(f.MinVersion)
+		if err != nil {
+			return "", fmt.Errorf("invalid minVersion: %v", err)
+		}
+		if v.LessThan(min) {
+			return fmt.Sprintf("requires Kubernetes %s or later", f.MinVersion), nil
+		}
+	}
+	if f.MaxVersion != "" {
+		max, err := version.ParseGeneric(f.MaxVersion)
+		if err != nil {
+			return "", fmt.Errorf("invalid maxVersion: %v", err)
+		}
+		if max.LessThan(v) {
+			return fmt.Sprintf("requires Kubernetes %s or earlier", f.MaxVersion), nil
+		}
+	}
+	return "", nil
+}
+
+// substitute replaces the variables of a template held by data with their
+// values. Other variables, such as those of a shell command, are left alone.
+func (f FileExpectations) substitute(data []byte) []byte {
+	for name, value := range f.Substitute {
+		re := regexp.MustCompile(`\$(` + regexp.QuoteMeta(name) + `\b|\{` + regexp.QuoteMeta(name) + `\})`)
+		data = re.ReplaceAllLiteral(data, []byte(value))
+	}
+	return data
+}
+
+// Matches reports whether w is the expected warning for a warning about
+// fieldRef with the given message.
+func (w ExpectedWarning) Matches(fieldRef, message string) bool {
+	return (w.FieldRef == "" || w.FieldRef == fieldRef) && strings.Contains(message, w.Message)
+}
+
+// CustomResourceDefinition returns a CustomResourceDefinition serving the
+// kind of the stub with its schema.
+func (s KindStub) CustomResourceDefinition() (*apiextensions.CustomResourceDefinition, error) {
+	gv, err := schema.ParseGroupVersion(s.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if gv.Group == "" || s.Kind == "" {
+		return nil, fmt.Errorf("stub %s %s: group and kind must be set", s.APIVersion, s.Kind)
+	}
+	if s.Schema == nil {
+		return nil, fmt.Errorf("stub %s %s: schema must be set", s.APIVersion, s.Kind)
+	}
+	props := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(s.Schema, props, nil); err != nil {
+		return nil, err
+	}
+	plural := strings.ToLower(s.Kind) + "s"
+	scope := apiextensions.ClusterScoped
+	if s.Namespaced {
+		scope = apiextensions.NamespaceScoped
+	}
+	crd := &apiextensions.CustomResourceDefinition{}
+	crd.Name = plural + "." + gv.Group
+	crd.Spec = apiextensions.CustomResourceDefinitionSpec{
+		Group: gv.Group,
+		Names: apiextensions.CustomResourceDefinitionNames{
+			Plural:   plural,
+			Singular: strings.ToLower(s.Kind),
+			Kind:     s.Kind,
+			ListKind: s.Kind + "List",
+		},
+		Scope: scope,
+		Versions: []apiextensions.CustomResourceDefinitionVersion{{
+			Name:    gv.Version,
+			Served:  true,
+			Storage: true,
+			Schema:  &apiextensions.CustomResourceValidation{OpenAPIV3Schema: props},
+		}},
+	}
+	return crd, nil
+}
+
+// LoadManifest reads the ManifestFile in dir. A directory without one gets
+// an empty manifest.
+func LoadManifest(dir string) (*Manifest, error) {
+	manifest := &Manifest{}
+	path := filepath.Join(dir, ManifestFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, manifest); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	for name := range manifest.Files {
+		fn := filepath.Join(dir, name)
+		_, err1 := os.Stat(fn + ".yaml")
+		_, err2 := os.Stat(fn + ".json")
+		if err1 != nil && err2 != nil {
+			return nil, fmt.Errorf("%s: expectations defined for non-existent file %s", path, name)
+		}
+	}
+	for name, expected := range manifest.Files {
+		for _, scenario := range expected.Admission {
+			if err := scenario.validate(dir); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", path, name, err)
+			}
+		}
+		for _, file := range expected.Context {
+			if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+				return nil, fmt.Errorf("%s: %s: context refers to non-existent file %s", path, name, file)
+			}
+		}
+		if expected.PodSecurity != "" {
+			if _, err := api.ParseLevel(expected.PodSecurity); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", path, name, err)
+			}
+		}
+		if e := expected.Expect; e != nil {
+			if e.Result != ExpectInvalid && e.Result != ExpectRejected {
+				return nil, fmt.Errorf("%s: %s: expected result must be %q or %q, got %q", path, name, ExpectInvalid, ExpectRejected, e.Result)
+			}
+			if len(expected.Kinds) > 0 && (e.Document < 0 || e.Document >= len(expected.Kinds)) {
+				return nil, fmt.Errorf("%s: %s: expected failure of non-existent document %d", path, name, e.Document)
+			}
+		}
+	}
+	for _, external := range manifest.Externals {
+		if err := external.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	for _, stub := range manifest.Stubs {
+		if _, err := stub.CustomResourceDefinition(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return manifest, nil
+}
+
+// validate checks that the scenario is complete and refers to files of dir,
+// the directory of the manifest.
+func (s AdmissionScenario) validate(dir string) error {
+	if s.Name == "" {
+		return fmt.Errorf("admission scenario without a name")
+	}
+	if len(s.Requests) == 0 {
+		return fmt.Errorf("admission scenario %q has no requests", s.Name)
+	}
+	files := append(append([]string{}, s.Bindings...), s.Params...)
+	for _, r := range s.Requests {
+		if r.Expect != AdmissionAdmit && r.Expect != AdmissionDeny {
+			return fmt.Errorf("admission scenario %q: expect must be %q or %q, got %q", s.Name, AdmissionAdmit, AdmissionDeny, r.Expect)
+		}
+		files = append(files, r.Object)
+	}
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			return fmt.Errorf("admission scenario %q refers to non-existent file %s", s.Name, file)
+		}
+	}
+	return nil
+}
+
+// validate checks that the external can resolve references.
+func (e ExternalObject) validate() error {
+	if e.Kind == "" {
+		return fmt.Errorf("external object without a kind")
+	}
+	if e.Name == "" && len(e.Labels) == 0 {
+		return fmt.Errorf("external %s without a name or labels", e.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"encoding/json"
+
+	apiservervalidation "k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	generatedopenapi "k8s.io/kubernetes/pkg/generated/openapi"
+)
+
+// builtinSchemas returns the resolver of the OpenAPI schemas of every
+// built-in kind, including those of the API groups the examples are not
+// decoded with.
+func (c *Checker) builtinSchemas() *resolver.DefinitionsSchemaResolver {
+	c.builtinSchemasOnce.Do(func() {
+		c.builtinSchemaResolver = resolver.NewDefinitionsSchemaResolver(generatedopenapi.GetOpenAPIDefinitions, legacyscheme.Scheme, clientgoscheme.Scheme)
+	})
+	return c.builtinSchemaResolver
+}
+
+// ValidateOpenAPI validates a JSON document against s, the OpenAPI schema of
+// its built-in kind. It is the fallback for the kinds ValidateObject does
+// not validate, and only catches what the schema tells, such as a field of
+// the wrong type, along with invalid metadata.
+func ValidateOpenAPI(data []byte, s *spec.Schema) field.ErrorList {
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &u.Object); err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath(""), err)}
+	}
+	// The schema does not tell the scope of the kind, take that of the
+	// object.
+	namespaced := u.GetNamespace() != ""
+	errors := validation.ValidateObjectMetaAccessor(u, namespaced, validation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	errors = append(errors, apiservervalidation.ValidateCustomResource(nil, u.UnstructuredContent(), apiservervalidation.NewSchemaValidatorFromOpenAPI(s))...)
+	return errors
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/pod-security-admission/admission"
+	"k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+)
+
+// podSecurityEvaluator checks pods against the Pod Security Standards, as the
+// PodSecurity admission plugin does.
+var podSecurityEvaluator = func() policy.Evaluator {
+	evaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		panic(err)
+	}
+	return evaluator
+}()
+
+// PodSecurity is the outcome of evaluating the Pod Security Standards against
+// the pod of a document, or the pod template of a workload.
+type PodSecurity struct {
+	// Level is the strictest level the pod satisfies.
+	Level api.Level
+	// Violated is the next stricter level, which the pod does not satisfy.
+	// It is empty for restricted pods.
+	Violated api.Level
+	// Violations are the reasons the pod does not satisfy the Violated
+	// level, as the PodSecurity admission plugin words them.
+	Violations []string
+}
+
+func (p PodSecurity) String() string {
+	if p.Violated == "" {
+		return string(p.Level)
+	}
+	return fmt.Sprintf("%s, violates %s: %s", p.Level, p.Violated, strings.Join(p.Violations, ", "))
+}
+
+// podSecurity evaluates the Pod Security Standards of Kubernetes release v
+// against the pod or pod template of an internal object declared in version
+// gvk. It returns nil for objects without one.
+func podSecurity(obj runtime.Object, gvk schema.GroupVersionKind, v *version.Version) (*PodSecurity, error) {
+	extractor := admission.DefaultPodSpecExtractor{}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	if !extractor.HasPodSpec(gvr.GroupResource()) {
+		return nil, nil
+	}
+	// the extractor reads the external types the plugin is given
+	external, err := legacyscheme.Scheme.ConvertToVersion(obj, gvk.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+	metadata, spec, err := extractor.ExtractPodSpec(external)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PodSecurity{Level: api.LevelPrivileged}
+	lv := api.LevelVersion{Version: api.MajorMinorVersion(int(v.Major()), int(v.Minor()))}
+	for _, level := range []api.Level{api.LevelRestricted, api.LevelBaseline} {
+		lv.Level = level
+		aggregate := policy.AggregateCheckResults(podSecurityEvaluator.EvaluatePod(lv, metadata, spec))
+		if aggregate.Allowed {
+			result.Level = level
+			break
+		}
+		result.Violated = level
+		result.Violations = nil
+		for i, reason := range aggregate.ForbiddenReasons {
+			if detail := aggregate.ForbiddenDetails[i]; detail != "" {
+				reason += " (" + detail + ")"
+			}
+			result.Violations = append(result.Violations, reason)
+		}
+	}
+	return result, nil
+}
+
+// checkPodSecurity checks that the strictest level of the Pod Security
+// Standards all the pods and pod templates of a file satisfy is the expected
+// one.
+func checkPodSecurity(result *FileResult, expected string) {
+	if expected == "" || result.Failed() {
+		return
+	}
+	var level api.Level
+	for _, doc := range result.Documents {
+		if doc.PodSecurity == nil {
+			continue
+		}
+		if level == "" || api.CompareLevels(doc.PodSecurity.Level, level) < 0 {
+			level = doc.PodSecurity.Level
+		}
+	}
+	if level == "" {
+		result.Errors = append(result.Errors, fmt.Errorf("pod security level %s expected for a file without pods", expected))
+		return
+	}
+	if string(level) != expected {
+		result.Errors = append(result.Errors, fmt.Errorf("pods satisfy the %s level of the Pod Security Standards, expected %s", level, expected))
+	}
+}
+
+// WritePodSecurity writes a line per pod or pod template of the checked
+// files to w, with the strictest level of the Pod Security Standards it
+// satisfies.
+func WritePodSecurity(w io.Writer, results []FileResult) error {
+	for _, r := range results {
+		for _, doc := range r.Documents {
+			if doc.PodSecurity == nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s: document %d (%s): %s\n", r.Path, doc.Index, doc.Kind.Kind, doc.PodSecurity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
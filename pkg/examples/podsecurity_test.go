@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/pod-security-admission/api"
+)
+
+const restrictedPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: restricted
+spec:
+  securityContext:
+    runAsNonRoot: true
+    seccompProfile:
+      type: RuntimeDefault
+  containers:
+  - name: nginx
+    image: nginx
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: ["ALL"]
+`
+
+const privilegedDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: privileged
+spec:
+  selector:
+    matchLabels:
+      app: privileged
+  template:
+    metadata:
+      labels:
+        app: privileged
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+        securityContext:
+          privileged: true
+`
+
+func TestPodSecurity(t *testing.T) {
+	kubeVersion, err := TargetVersion("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		source        string
+		wantLevel     api.Level
+		wantViolated  api.Level
+		wantViolation string
+	}{{
+		name:      "restricted",
+		source:    restrictedPod,
+		wantLevel: api.LevelRestricted,
+	}, {
+		name:          "baseline",
+		source:        testPod,
+		wantLevel:     api.LevelBaseline,
+		wantViolated:  api.LevelRestricted,
+		wantViolation: "allowPrivilegeEscalation != false",
+	}, {
+		name:          "privileged",
+		source:        privilegedDeployment,
+		wantLevel:     api.LevelPrivileged,
+		wantViolated:  api.LevelBaseline,
+		wantViolation: "privileged",
+	}, {
+		name:   "without pod",
+		source: testConfigMap,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, gvk := decodeTestObject(t, tc.source)
+			result, err := podSecurity(obj, gvk, kubeVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantLevel == "" {
+				if result != nil {
+					t.Errorf("expected no pod, got %v", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatal("expected a pod")
+			}
+			if result.Level != tc.wantLevel || result.Violated != tc.wantViolated {
+				t.Errorf("expected level %s violating %q, got %v", tc.wantLevel, tc.wantViolated, result)
+			}
+			if tc.wantViolation != "" && !strings.Contains(strings.Join(result.Violations, "; "), tc.wantViolation) {
+				t.Errorf("expected a violation %q, got %v", tc.wantViolation, result.Violations)
+			}
+		})
+	}
+}
+
+func TestCheckPodSecurity(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		levels   []api.Level
+		expected string
+		wantErr  bool
+	}{{
+		name:     "expected",
+		levels:   []api.Level{api.LevelRestricted, api.LevelBaseline},
+		expected: "baseline",
+	}, {
+		name:     "unexpected",
+		levels:   []api.Level{api.LevelRestricted, api.LevelBaseline},
+		expected: "restricted",
+		wantErr:  true,
+	}, {
+		name:     "without pod",
+		expected: "restricted",
+		wantErr:  true,
+	}, {
+		name:   "not expected",
+		levels: []api.Level{api.LevelPrivileged},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := &FileResult{Path: "pods.yaml"}
+			for i, level := range tc.levels {
+				result.Documents = append(result.Documents, DocumentResult{Index: i, PodSecurity: &PodSecurity{Level: level}})
+			}
+			checkPodSecurity(result, tc.expected)
+			if got := len(result.Errors) > 0; got != tc.wantErr {
+				t.Errorf("expected an error: %v, got %v", tc.wantErr, result.Errors)
+			}
+		})
+	}
+}
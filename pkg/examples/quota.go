@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/quota/v1/evaluator/core"
+)
+
+// quotaEvaluators measure the usage of the resources of the core group, the
+// way the ResourceQuota admission plugin and the quota controller do, keyed
+// by group resource.
+var quotaEvaluators = func() map[schema.GroupResource]quota.Evaluator {
+	evaluators := map[schema.GroupResource]quota.Evaluator{}
+	// Without a lister, the evaluators only measure the objects they are
+	// given.
+	for _, evaluator := range core.NewEvaluators(nil) {
+		evaluators[evaluator.GroupResource()] = evaluator
+	}
+	return evaluators
+}()
+
+// quotaEvaluator returns the evaluator measuring the usage of an internal
+// object, if any.
+func quotaEvaluator(obj runtime.Object) quota.Evaluator {
+	kinds, _, err := legacyscheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(kinds[0])
+	return quotaEvaluators[gvr.GroupResource()]
+}
+
+// resourceQuotas returns the ResourceQuotas among the internal objects of
+// the context of a file, with their usage set to that of the other objects
+// of context, as the quota controller would set it once they are created.
+func resourceQuotas(context []runtime.Object) ([]corev1.ResourceQuota, error) {
+	var quotas []corev1.ResourceQuota
+	for _, obj := range context {
+		internal, ok := obj.(*api.ResourceQuota)
+		if !ok {
+			continue
+		}
+		resourceQuota := corev1.ResourceQuota{}
+		if err := legacyscheme.Scheme.Convert(internal, &resourceQuota, nil); err != nil {
+			return nil, err
+		}
+		resourceQuota.Status.Hard = resourceQuota.Spec.Hard
+		resourceQuota.Status.Used = corev1.ResourceList{}
+		quotas = append(quotas, resourceQuota)
+	}
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	for _, obj := range context {
+		evaluator := quotaEvaluator(obj)
+		if evaluator == nil {
+			continue
+		}
+		usage, err := evaluator.Usage(obj)
+		if err != nil {
+			return nil, err
+		}
+		for i := range quotas {
+			match, err := evaluator.Matches(&quotas[i], obj)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				used := quota.Mask(usage, quota.ResourceNames(quotas[i].Status.Hard))
+				quotas[i].Status.Used = quota.Add(quotas[i].Status.Used, used)
+			}
+		}
+	}
+	for i := range quotas {
+		for name, hard := range quotas[i].Status.Hard {
+			if _, ok := quotas[i].Status.Used[name]; !ok {
+				quotas[i].Status.Used[name] = *resource.NewQuantity(0, hard.Format)
+			}
+		}
+	}
+	return quotas, nil
+}
+
+// quotaValidate checks that creating an internal object does not exceed
+// quotas, as the ResourceQuota admission plugin does, without limiting the
+// consumption of any resource by default.
+func quotaValidate(obj runtime.Object, quotas []corev1.ResourceQuota) []error {
+	evaluator := quotaEvaluator(obj)
+	if evaluator == nil || len(quotas) == 0 {
+		return nil
+	}
+
+	var matching []corev1.ResourceQuota
+	for _, resourceQuota := range quotas {
+		match, err := evaluator.Matches(&resourceQuota, obj)
+		if err != nil {
+			return []error{err}
+		}
+		if !match {
+			continue
+		}
+		restricted := evaluator.MatchingResources(quota.ResourceNames(resourceQuota.Status.Hard))
+		if err := evaluator.Constraints(restricted, obj); err != nil {
+			return []error{fmt.Errorf("failed quota: %s: %v", resourceQuota.Name, err)}
+		}
+		matching = append(matching, resourceQuota)
+	}
+
+	usage, err := evaluator.Usage(obj)
+	if err != nil {
+		return []error{err}
+	}
+	usage = quota.RemoveZeros(usage)
+	for _, resourceQuota := range matching {
+		requested := quota.Mask(usage, quota.ResourceNames(resourceQuota.Status.Hard))
+		used := quota.Add(resourceQuota.Status.Used, requested)
+		if allowed, exceeded := quota.LessThanOrEqual(quota.Mask(used, quota.ResourceNames(requested)), resourceQuota.Status.Hard); !allowed {
+			return []error{fmt.Errorf("exceeded quota: %s, requested: %s, used: %s, limited: %s",
+				resourceQuota.Name,
+				formatResourceList(quota.Mask(requested, exceeded)),
+				formatResourceList(quota.Mask(resourceQuota.Status.Used, exceeded)),
+				formatResourceList(quota.Mask(resourceQuota.Status.Hard, exceeded)))}
+		}
+	}
+	return nil
+}
+
+// formatResourceList formats resources the way the ResourceQuota admission
+// plugin does in its errors.
+func formatResourceList(resources corev1.ResourceList) string {
+	var parts []string
+	for name, value := range resources {
+		parts = append(parts, string(name)+"="+value.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
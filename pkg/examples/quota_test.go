@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const podCountQuota = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: pod-count
+spec:
+  hard:
+    pods: "2"
+`
+
+const cpuQuota = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: cpu
+spec:
+  hard:
+    requests.cpu: "1"
+`
+
+func TestQuotaValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		context []string
+		wantErr string
+	}{{
+		name:    "within quota",
+		context: []string{podCountQuota, testPod},
+	}, {
+		name:    "without quota",
+		context: []string{testPod, testPod},
+	}, {
+		name:    "exceeded quota",
+		context: []string{podCountQuota, testPod, testPod},
+		wantErr: "exceeded quota: pod-count, requested: pods=1, used: pods=2, limited: pods=2",
+	}, {
+		name:    "unconstrained resource",
+		context: []string{cpuQuota},
+		wantErr: "failed quota: cpu",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			var context []runtime.Object
+			for _, source := range tc.context {
+				obj, _ := decodeTestObject(t, source)
+				context = append(context, obj)
+			}
+			quotas, err := resourceQuotas(context)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pod, _ := decodeTestObject(t, testPod)
+			errs := quotaValidate(pod, quotas)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("expected no error, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantErr) {
+				t.Errorf("expected %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/pkg/apis/batch"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/networking"
+	"k8s.io/kubernetes/pkg/apis/rbac"
+)
+
+// Reference is a reference of an object to another one it is applied with,
+// such as the Secret a Pod mounts.
+type Reference struct {
+	// Field holding the reference.
+	Field *field.Path
+	// Kind of the object referred to.
+	Kind string
+	// Namespace of the object referred to, empty for cluster-scoped objects
+	// or when it is that of the kubectl context.
+	Namespace string
+	// Name of the object referred to.
+	Name string
+	// Selector of the objects referred to, such as the pods of a Service,
+	// in place of a name.
+	Selector labels.Selector
+}
+
+// notFound returns the error reporting the reference as dangling.
+func (r Reference) notFound() *field.Error {
+	if r.Selector != nil {
+		return &field.Error{Type: field.ErrorTypeNotFound, Field: r.Field.String(), BadValue: r.Selector.String(),
+			Detail: fmt.Sprintf("no %s or pod template among the examples of the directory or its externals matches the selector", r.Kind)}
+	}
+	return &field.Error{Type: field.ErrorTypeNotFound, Field: r.Field.String(), BadValue: r.Name,
+		Detail: fmt.Sprintf("no %s among the examples of the directory or its externals", r.Kind)}
+}
+
+// referenceTarget is an object references can resolve to.
+type referenceTarget struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Labels    labels.Set
+}
+
+// resolves reports whether ref refers to the target. Objects without a
+// namespace are created in the namespace of the kubectl context, which any
+// namespace matches.
+func (t referenceTarget) resolves(ref Reference) bool {
+	if t.Kind != ref.Kind {
+		return false
+	}
+	if t.Namespace != "" && ref.Namespace != "" && t.Namespace != ref.Namespace {
+		return false
+	}
+	if ref.Selector != nil {
+		return ref.Selector.Matches(t.Labels)
+	}
+	return t.Name == ref.Name
+}
+
+// builtinObject reports whether ref refers to an object every cluster, or
+// every namespace of a cluster, provides.
+func builtinObject(ref Reference) bool {
+	switch ref.Kind {
+	case "ServiceAccount":
+		return ref.Name == "default"
+	case "ConfigMap":
+		return ref.Name == "kube-root-ca.crt"
+	case "Service":
+		return ref.Name == "kubernetes"
+	case "ClusterRole":
+		switch ref.Name {
+		case "cluster-admin", "admin", "edit", "view":
+			return true
+		}
+		return strings.HasPrefix(ref.Name, "system:")
+	}
+	return false
+}
+
+// referenceTargets returns the objects an internal object provides to the
+// references of others: itself and, for a workload, the pods of its pod
+// template.
+func referenceTargets(obj runtime.Object) []referenceTarget {
+	kinds, _, err := legacyscheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	targets := []referenceTarget{{
+		Kind:      kinds[0].Kind,
+		Namespace: accessor.GetNamespace(),
+		Name:      accessor.GetName(),
+		Labels:    accessor.GetLabels(),
+	}}
+	if _, ok := obj.(*api.Pod); !ok {
+		if template, _ := podTemplate(obj); template != nil {
+			targets = append(targets, referenceTarget{Kind: "Pod", Namespace: accessor.GetNamespace(), Labels: template.Labels})
+		}
+	}
+	return targets
+}
+
+// podTemplate returns the pod template of an internal object, or the pod
+// itself as a template, along with the path of its spec.
+func podTemplate(obj runtime.Object) (*api.PodTemplateSpec, *field.Path) {
+	spec := field.NewPath("spec")
+	switch o := obj.(type) {
+	case *api.Pod:
+		return &api.PodTemplateSpec{ObjectMeta: o.ObjectMeta, Spec: o.Spec}, spec
+	case *api.PodTemplate:
+		return &o.Template, field.NewPath("template", "spec")
+	case *api.ReplicationController:
+		return o.Spec.Template, spec.Child("template", "spec")
+	case *apps.Deployment:
+		return &o.Spec.Template, spec.Child("template", "spec")
+	case *apps.ReplicaSet:
+		return &o.Spec.Template, spec.Child("template", "spec")
+	case *apps.StatefulSet:
+		return &o.Spec.Template, spec.Child("template", "spec")
+	case *apps.DaemonSet:
+		return &o.Spec.Template, spec.Child("template", "spec")
+	case *batch.Job:
+		return &o.Spec.Template, spec.Child("template", "spec")
+	case *batch.CronJob:
+		return &o.Spec.JobTemplate.Spec.Template, spec.Child("jobTemplate", "spec", "template", "spec")
+	}
+	return nil, nil
+}
+
+// references returns the references of an internal object to other objects
+// it is applied with. Optional references are left out.
+func references(obj runtime.Object) []Reference {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+	namespace := accessor.GetNamespace()
+	spec := field.NewPath("spec")
+
+	var refs []Reference
+	if template, path := podTemplate(obj); template != nil {
+		refs = append(refs, podSpecReferences(&template.Spec, path, namespace)...)
+	}
+	switch o := obj.(type) {
+	case *api.Service:
+		if len(o.Spec.Selector) > 0 {
+			refs = append(refs, Reference{Field: spec.Child("selector"), Kind: "Pod", Namespace: namespace, Selector: labels.SelectorFromSet(o.Spec.Selector)})
+		}
+	case *apps.StatefulSet:
+		if o.Spec.ServiceName != "" {
+			refs = append(refs, Reference{Field: spec.Child("serviceName"), Kind: "Service", Namespace: namespace, Name: o.Spec.ServiceName})
+		}
+	case *autoscaling.HorizontalPodAutoscaler:
+		refs = append(refs, Reference{Field: spec.Child("scaleTargetRef", "name"), Kind: o.Spec.ScaleTargetRef.Kind, Namespace: namespace, Name: o.Spec.ScaleTargetRef.Name})
+	case *networking.Ingress:
+		if b := o.Spec.DefaultBackend; b != nil && b.Service != nil {
+			refs = append(refs, Reference{Field: spec.Child("defaultBackend", "service", "name"), Kind: "Service", Namespace: namespace, Name: b.Service.Name})
+		}
+		for i, rule := range o.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for j, p := range rule.HTTP.Paths {
+				if p.Backend.Service != nil {
+					path := spec.Child("rules").Index(i).Child("http", "paths").Index(j).Child("backend", "service", "name")
+					refs = append(refs, Reference{Field: path, Kind: "Service", Namespace: namespace, Name: p.Backend.Service.Name})
+				}
+			}
+		}
+	case *rbac.RoleBinding:
+		refs = append(refs, roleRefReference(o.RoleRef, namespace))
+		refs = append(refs, subjectReferences(o.Subjects, namespace)...)
+	case *rbac.ClusterRoleBinding:
+		refs = append(refs, roleRefReference(o.RoleRef, ""))
+		refs = append(refs, subjectReferences(o.Subjects, "")...)
+	}
+	return refs
+}
+
+// podSpecReferences returns the references of a pod spec at path, in
+// namespace, to the objects its pods need to start.
+func podSpecReferences(spec *api.PodSpec, path *field.Path, namespace string) []Reference {
+	var refs []Reference
+	ref := func(field *field.Path, kind, name string, optional *bool) {
+		if name != "" && (optional == nil || !*optional) {
+			refs = append(refs, Reference{Field: field, Kind: kind, Namespace: namespace, Name: name})
+		}
+	}
+
+	for i, v := range spec.Volumes {
+		p := path.Child("volumes").Index(i)
+		switch {
+		case v.ConfigMap != nil:
+			ref(p.Child("configMap", "name"), "ConfigMap", v.ConfigMap.Name, v.ConfigMap.Optional)
+		case v.Secret != nil:
+			ref(p.Child("secret", "secretName"), "Secret", v.Secret.SecretName, v.Secret.Optional)
+		case v.PersistentVolumeClaim != nil:
+			ref(p.Child("persistentVolumeClaim", "claimName"), "PersistentVolumeClaim", v.PersistentVolumeClaim.ClaimName, nil)
+		case v.Projected != nil:
+			for j, s := range v.Projected.Sources {
+				sp := p.Child("projected", "sources").Index(j)
+				if s.ConfigMap != nil {
+					ref(sp.Child("configMap", "name"), "ConfigMap", s.ConfigMap.Name, s.ConfigMap.Optional)
+				}
+				if s.Secret != nil {
+					ref(sp.Child("secret", "name"), "Secret", s.Secret.Name, s.Secret.Optional)
+				}
+			}
+		}
+	}
+	containers := func(p *field.Path, containers []api.Container) {
+		for i, c := range containers {
+			cp := p.Index(i)
+			for j, e := range c.EnvFrom {
+				ep := cp.Child("envFrom").Index(j)
+				if e.ConfigMapRef != nil {
+					ref(ep.Child("configMapRef", "name"), "ConfigMap", e.ConfigMapRef.Name, e.ConfigMapRef.Optional)
+				}
+				if e.SecretRef != nil {
+					ref(ep.Child("secretRef", "name"), "Secret", e.SecretRef.Name, e.SecretRef.Optional)
+				}
+			}
+			for j, e := range c.Env {
+				if e.ValueFrom == nil {
+					continue
+				}
+				ep := cp.Child("env").Index(j).Child("valueFrom")
+				if s := e.ValueFrom.ConfigMapKeyRef; s != nil {
+					ref(ep.Child("configMapKeyRef", "name"), "ConfigMap", s.Name, s.Optional)
+				}
+				if s := e.ValueFrom.SecretKeyRef; s != nil {
+					ref(ep.Child("secretKeyRef", "name"), "Secret", s.Name, s.Optional)
+				}
+			}
+		}
+	}
+	containers(path.Child("initContainers"), spec.InitContainers)
+	containers(path.Child("containers"), spec.Containers)
+	ref(path.Child("serviceAccountName"), "ServiceAccount", spec.ServiceAccountName, nil)
+	for i, s := range spec.ImagePullSecrets {
+		ref(path.Child("imagePullSecrets").Index(i).Child("name"), "Secret", s.Name, nil)
+	}
+	return refs
+}
+
+// roleRefReference returns the reference of a binding in namespace to its
+// role, or to a ClusterRole.
+func roleRefReference(roleRef rbac.RoleRef, namespace string) Reference {
+	if roleRef.Kind == "ClusterRole" {
+		namespace = ""
+	}
+	return Reference{Field: field.NewPath("roleRef", "name"), Kind: roleRef.Kind, Namespace: namespace, Name: roleRef.Name}
+}
+
+// subjectReferences returns the references of a binding in namespace to the
+// ServiceAccounts among its subjects.
+func subjectReferences(subjects []rbac.Subject, namespace string) []Reference {
+	var refs []Reference
+	for i, s := range subjects {
+		if s.Kind != rbac.ServiceAccountKind {
+			continue
+		}
+		ns := s.Namespace
+		if ns == "" {
+			ns = namespace
+		}
+		refs = append(refs, Reference{Field: field.NewPath("subjects").Index(i).Child("name"), Kind: s.Kind, Namespace: ns, Name: s.Name})
+	}
+	return refs
+}
+
+// ResolveReferences resolves the references of the objects of the checked
+// files of each directory to the other objects of these files, and to the
+// externals of the manifest of the directory. Dangling references are
+// reported as warnings, which the manifest of their file may expect, or as
+// errors when the checker fails on dangling references they do not. The
+// warnings a file expects that none of its documents raised are reported
+// then.
+func (c *Checker) ResolveReferences(results []FileResult) {
+	dirs := map[string][]int{}
+	for i, r := range results {
+		dir := filepath.Dir(r.Path)
+		dirs[dir] = append(dirs[dir], i)
+	}
+	for dir, files := range dirs {
+		var dirResults []FileResult
+		for _, i := range files {
+			dirResults = append(dirResults, results[i])
+		}
+		targets := c.directoryTargets(dir, dirResults)
+		for _, i := range files {
+			c.resolveReferences(&results[i], targets)
+		}
+	}
+}
+
+// directoryTargets returns the objects the references of the objects of the
+// files of dir resolve to: those of the files and the externals of the
+// manifest of dir.
+func (c *Checker) directoryTargets(dir string, results []FileResult) []referenceTarget {
+	var targets []referenceTarget
+	if manifest, err := c.manifest(dir); err == nil {
+		for _, e := range manifest.Externals {
+			targets = append(targets, e.target())
+		}
+	}
+	for _, r := range results {
+		for _, doc := range r.Documents {
+			if doc.Object != nil {
+				targets = append(targets, referenceTargets(doc.Object)...)
+			}
+		}
+	}
+	return targets
+}
+
+// resolveReferences resolves the references of the objects of a file to
+// targets, reporting those that do not resolve.
+func (c *Checker) resolveReferences(result *FileResult, targets []referenceTarget) {
+	for j := range result.Documents {
+		doc := &result.Documents[j]
+		if doc.Object == nil {
+			continue
+		}
+		for _, ref := range references(doc.Object) {
+			if builtinObject(ref) || resolved(ref, targets) {
+				continue
+			}
+			err := ref.notFound()
+			w := Warning{FieldRef: err.Field, Message: err.ErrorBody()}
+			w.Expected = expectedWarning(result.expectedWarnings, w)
+			if !w.Expected && c.FailOnDanglingReferences {
+				doc.Errors = append(doc.Errors, err)
+			} else {
+				doc.Warnings = append(doc.Warnings, w)
+			}
+		}
+	}
+	checkRaisedWarnings(result)
+	locateErrors(result)
+}
+
+func resolved(ref Reference, targets []referenceTarget) bool {
+	for _, t := range targets {
+		if t.resolves(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// target returns the object an external provides to references.
+func (e ExternalObject) target() referenceTarget {
+	return referenceTarget{Kind: e.Kind, Namespace: e.Namespace, Name: e.Name, Labels: e.Labels}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const configMapPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: nginx
+    envFrom:
+    - configMapRef:
+        name: web-config
+`
+
+const webConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  color: blue
+`
+
+const expectDanglingConfigMap = `files:
+  pod:
+    warnings:
+    - message: no ConfigMap among the examples
+`
+
+func TestResolveReferences(t *testing.T) {
+	kubeVersion, err := TargetVersion("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name               string
+		files              map[string]string
+		failOnWarnings     bool
+		failOnDangling     bool
+		wantWarning        bool
+		wantExpected       bool
+		wantDocumentErrors int
+		wantFileErrors     int
+	}{{
+		name:        "dangling",
+		files:       map[string]string{"pod.yaml": configMapPod},
+		wantWarning: true,
+	}, {
+		name:           "dangling with fail on warnings",
+		files:          map[string]string{"pod.yaml": configMapPod},
+		failOnWarnings: true,
+		wantWarning:    true,
+	}, {
+		name:               "dangling with fail on dangling references",
+		files:              map[string]string{"pod.yaml": configMapPod},
+		failOnDangling:     true,
+		wantDocumentErrors: 1,
+	}, {
+		name:           "expected dangling",
+		files:          map[string]string{"pod.yaml": configMapPod, ManifestFile: expectDanglingConfigMap},
+		failOnWarnings: true,
+		failOnDangling: true,
+		wantWarning:    true,
+		wantExpected:   true,
+	}, {
+		name:  "resolved",
+		files: map[string]string{"pod.yaml": configMapPod, "configmap.yaml": webConfigMap},
+	}, {
+		name:           "expected dangling resolved",
+		files:          map[string]string{"pod.yaml": configMapPod, "configmap.yaml": webConfigMap, ManifestFile: expectDanglingConfigMap},
+		wantFileErrors: 1,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checker := NewChecker(dir, kubeVersion)
+			checker.FailOnWarnings = tc.failOnWarnings
+			checker.FailOnDanglingReferences = tc.failOnDangling
+
+			var pod *FileResult
+			err := checker.Check(func(r FileResult) {
+				if filepath.Base(r.Path) == "pod.yaml" {
+					pod = &r
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pod == nil || len(pod.Documents) != 1 {
+				t.Fatalf("expected the pod to be checked, got %+v", pod)
+			}
+
+			doc := pod.Documents[0]
+			if len(doc.Errors) != tc.wantDocumentErrors {
+				t.Errorf("expected %d document errors, got %v", tc.wantDocumentErrors, doc.Errors)
+			}
+			if len(pod.Errors) != tc.wantFileErrors {
+				t.Errorf("expected %d file errors, got %v", tc.wantFileErrors, pod.Errors)
+			}
+			if got := len(doc.Warnings) > 0; got != tc.wantWarning {
+				t.Fatalf("expected a warning: %v, got %v", tc.wantWarning, doc.Warnings)
+			}
+			if tc.wantWarning {
+				w := doc.Warnings[0]
+				if w.Expected != tc.wantExpected {
+					t.Errorf("expected the warning to be expected: %v, got %+v", tc.wantExpected, w)
+				}
+				if w.FieldRef != "spec.containers[0].envFrom[0].configMapRef.name" {
+					t.Errorf("unexpected field of the warning: %q", w.FieldRef)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/website/pkg/examples/report"
+)
+
+// ReportRun returns the outcome of checking the files of the examples
+// directory root as a run of a report. The paths of the files are made
+// relative to the root of the website, for code review tools to link to
+// them.
+func ReportRun(root string, kubeVersion *version.Version, results []FileResult) report.Run {
+	// paths are left as they are when the website is not found
+	website, _ := WebsiteRoot(root)
+	run := report.Run{Root: relativePath(website, root), KubernetesVersion: kubeVersion.String(), Files: []report.File{}}
+	for _, r := range results {
+		run.Files = append(run.Files, reportFile(r, website))
+	}
+	return run
+}
+
+// reportFile returns the outcome of checking a file, with its path relative
+// to base when set.
+func reportFile(result FileResult, base string) report.File {
+	f := report.File{Path: relativePath(base, result.Path), Outcome: report.Passed}
+	switch {
+	case result.Skipped != "":
+		f.Outcome = report.Skipped
+		f.SkipReason = result.Skipped
+	case result.Failed():
+		f.Outcome = report.Failed
+	}
+	for _, err := range result.Errors {
+		f.Errors = append(f.Errors, reportProblem(err))
+	}
+
+	for _, doc := range result.Documents {
+		d := report.Document{
+			Index:      doc.Index,
+			APIVersion: doc.Kind.GroupVersion().String(),
+			Kind:       doc.Kind.Kind,
+			Outcome:    report.Passed,
+		}
+		if len(doc.Errors) > 0 || len(doc.Rejections) > 0 {
+			d.Outcome = report.Failed
+		}
+		for _, err := range doc.Errors {
+			d.Errors = append(d.Errors, reportProblem(err))
+		}
+		for _, err := range doc.Rejections {
+			p := reportProblem(err)
+			p.Message = "rejected: " + p.Message
+			d.Errors = append(d.Errors, p)
+		}
+		for _, err := range doc.ExpectedErrors {
+			d.ExpectedErrors = append(d.ExpectedErrors, reportProblem(err))
+		}
+		for _, w := range doc.Warnings {
+			p := report.Problem{Message: w.String(), Field: w.FieldRef, Expected: w.Expected}
+			if w.Location != nil {
+				p.Line, p.Column = w.Location.Line, w.Location.Column
+			}
+			d.Warnings = append(d.Warnings, p)
+		}
+		if doc.PodSecurity != nil {
+			d.PodSecurity = string(doc.PodSecurity.Level)
+		}
+		f.Documents = append(f.Documents, d)
+	}
+	return f
+}
+
+// relativePath returns path relative to base, with forward slashes, or path
+// itself when base is empty or path is not under it.
+func relativePath(base, path string) string {
+	if base == "" {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// reportProblem returns an error of a file or document along with the
+// position of the field it is about, when known.
+func reportProblem(err error) report.Problem {
+	p := report.Problem{Message: err.Error(), Field: errorField(err)}
+	if location, ok := ErrorLocation(err); ok {
+		p.Line, p.Column = location.Line, location.Column
+	}
+	return p
+}
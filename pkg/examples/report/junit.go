@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// The JUnit XML schema, as read by CI systems such as Prow and GitHub
+// Actions reporters.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML: a test suite per run and a test
+// case per file, failed by its errors and those of its documents. Warnings
+// are written to the output of the test case.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{}
+	for _, run := range r.Runs {
+		suite := junitTestSuite{Name: fmt.Sprintf("%s (Kubernetes %s)", run.Root, run.KubernetesVersion)}
+		for _, f := range run.Files {
+			tc := junitTestCase{Name: f.Path, ClassName: path.Dir(f.Path)}
+			switch f.Outcome {
+			case Skipped:
+				suite.Skipped++
+				tc.Skipped = &junitMessage{Message: f.SkipReason}
+			case Failed:
+				suite.Failures++
+				errs := f.messages(false)
+				tc.Failure = &junitMessage{Text: strings.Join(errs, "\n")}
+				if len(errs) > 0 {
+					tc.Failure.Message = errs[0]
+				}
+			}
+			tc.SystemOut = strings.Join(f.messages(true), "\n")
+			suite.Tests++
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// messages formats the errors of the file and of its documents, or the
+// warnings of its documents, along with their position.
+func (f File) messages(warnings bool) []string {
+	var lines []string
+	if !warnings {
+		for _, p := range f.Errors {
+			lines = append(lines, fmt.Sprintf("%s: %s", f.Path, p.Message))
+		}
+	}
+	for _, d := range f.Documents {
+		problems := d.Errors
+		if warnings {
+			problems = d.Warnings
+		}
+		for _, p := range problems {
+			lines = append(lines, fmt.Sprintf("%s: document %d (%s): %s", p.position(f.Path), d.Index, d.Kind, p.Message))
+		}
+	}
+	return lines
+}
+
+// position returns the position of the problem in the file at path, or the
+// path when it is not known.
+func (p Problem) position(path string) string {
+	if p.Line == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s:%d:%d", path, p.Line, p.Column)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report renders the outcome of validating example files as JSON,
+// JUnit XML or SARIF, for continuous integration systems and dashboards.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Outcome is the outcome of checking an example file or document.
+type Outcome string
+
+const (
+	Passed  Outcome = "passed"
+	Failed  Outcome = "failed"
+	Skipped Outcome = "skipped"
+)
+
+// Format is the format a report is written in.
+type Format string
+
+const (
+	JSON  Format = "json"
+	JUnit Format = "junit"
+	SARIF Format = "sarif"
+)
+
+// Formats lists the formats reports can be written in.
+var Formats = []Format{JSON, JUnit, SARIF}
+
+// Report is the outcome of validating the examples of one or more
+// directories.
+type Report struct {
+	Runs []Run `json:"runs"`
+}
+
+// Run is the outcome of validating the examples of a directory, such as
+// those of a locale.
+type Run struct {
+	// Root is the examples directory.
+	Root string `json:"root"`
+	// KubernetesVersion is the release the examples are validated against.
+	KubernetesVersion string `json:"kubernetesVersion"`
+	Files             []File `json:"files"`
+}
+
+// File is the outcome of checking an example file.
+type File struct {
+	// Path of the file, relative to the root of the repository when known.
+	Path    string  `json:"path"`
+	Outcome Outcome `json:"outcome"`
+	// SkipReason is the reason the file was not validated.
+	SkipReason string `json:"skipReason,omitempty"`
+	// Errors concern the file as a whole.
+	Errors    []Problem  `json:"errors,omitempty"`
+	Documents []Document `json:"documents,omitempty"`
+}
+
+// Document is the outcome of checking a document of an example file.
+type Document struct {
+	Index      int     `json:"index"`
+	APIVersion string  `json:"apiVersion,omitempty"`
+	Kind       string  `json:"kind,omitempty"`
+	Outcome    Outcome `json:"outcome"`
+	// Errors found decoding, validating or admitting the document.
+	Errors []Problem `json:"errors,omitempty"`
+	// ExpectedErrors are those of a document the manifest of its file
+	// expects to fail, which do not fail it.
+	ExpectedErrors []Problem `json:"expectedErrors,omitempty"`
+	Warnings       []Problem `json:"warnings,omitempty"`
+	// PodSecurity is the strictest level of the Pod Security Standards the
+	// pod or pod template of the document satisfies.
+	PodSecurity string `json:"podSecurity,omitempty"`
+}
+
+// Problem is an error or a warning found in an example file.
+type Problem struct {
+	Message string `json:"message"`
+	// Field the problem is about, such as spec.containers[0].image.
+	Field string `json:"field,omitempty"`
+	// Line and Column of the field in the file, counted from 1, when known.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Expected is set for the warnings the manifest of the file expects.
+	Expected bool `json:"expected,omitempty"`
+}
+
+// Write writes the report to w in the given format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case JSON:
+		return r.WriteJSON(w)
+	case JUnit:
+		return r.WriteJUnit(w)
+	case SARIF:
+		return r.WriteSARIF(w)
+	}
+	return fmt.Errorf("unknown report format %q, expected one of %v", format, Formats)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
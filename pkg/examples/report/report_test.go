@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var testReport = &Report{Runs: []Run{{
+	Root:              "content/en/examples",
+	KubernetesVersion: "1.30",
+	Files: []File{
+		{Path: "content/en/examples/pods/simple-pod.yaml", Outcome: Passed, Documents: []Document{
+			{Index: 0, APIVersion: "v1", Kind: "Pod", Outcome: Passed, PodSecurity: "baseline"},
+		}},
+		{Path: "content/en/examples/pods/invalid-pod.yaml", Outcome: Failed, Documents: []Document{
+			{Index: 0, APIVersion: "v1", Kind: "Pod", Outcome: Failed,
+				Errors:   []Problem{{Message: "spec.containers[0].image: Required value", Field: "spec.containers[0].image", Line: 7, Column: 5}},
+				Warnings: []Problem{{Message: "expected warning", Expected: true}, {Message: "unexpected warning"}},
+			},
+		}},
+		{Path: "content/en/examples/pods/profile.json", Outcome: Skipped, SkipReason: "not an API object"},
+	},
+}}}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport.Write(&buf, JSON); err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Runs) != 1 || len(got.Runs[0].Files) != 3 {
+		t.Fatalf("unexpected report: %s", buf.String())
+	}
+	if f := got.Runs[0].Files[1]; f.Outcome != Failed || f.Documents[0].Errors[0].Line != 7 {
+		t.Errorf("unexpected failed file: %+v", f)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport.Write(&buf, JUnit); err != nil {
+		t.Fatal(err)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Suites) != 1 {
+		t.Fatalf("expected 1 test suite, got %d", len(got.Suites))
+	}
+	suite := got.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Skipped != 1 {
+		t.Errorf("expected 3 tests, 1 failure and 1 skipped, got %d, %d and %d", suite.Tests, suite.Failures, suite.Skipped)
+	}
+	failure := suite.Cases[1].Failure
+	if failure == nil || !strings.Contains(failure.Text, "content/en/examples/pods/invalid-pod.yaml:7:5: document 0 (Pod): spec.containers[0].image: Required value") {
+		t.Errorf("unexpected failure: %+v", failure)
+	}
+	if skipped := suite.Cases[2].Skipped; skipped == nil || skipped.Message != "not an API object" {
+		t.Errorf("unexpected skipped: %+v", skipped)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport.Write(&buf, SARIF); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != sarifVersion || len(got.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+	results := got.Runs[0].Results
+	// the expected warning is left out
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %s", len(results), buf.String())
+	}
+	if r := results[0]; r.RuleID != ruleError || r.Level != "error" || r.Locations[0].PhysicalLocation.Region == nil || r.Locations[0].PhysicalLocation.Region.StartLine != 7 {
+		t.Errorf("unexpected error result: %+v", r)
+	}
+	if r := results[1]; r.RuleID != ruleWarning || r.Level != "warning" || r.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("unexpected warning result: %+v", r)
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testReport.Write(&bytes.Buffer{}, "html"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	// ToolName is the name of the tool in SARIF reports.
+	ToolName = "validate-examples"
+
+	// The rules of the results of SARIF reports.
+	ruleError   = "example-error"
+	ruleWarning = "example-warning"
+)
+
+// The subset of the SARIF 2.1.0 schema code scanning tools, such as GitHub's,
+// read to annotate pull requests.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes the report as a SARIF log: a run per run of the report,
+// with a result per error and per unexpected warning, located at the field
+// it is about when known.
+func (r *Report) WriteSARIF(w io.Writer) error {
+	log := sarifLog{Schema: sarifSchema, Version: sarifVersion}
+	for _, run := range r.Runs {
+		sr := sarifRun{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           ToolName,
+				InformationURI: "https://github.com/kubernetes/website/tree/main/content/en/examples",
+				Rules: []sarifRule{
+					{ID: ruleError, ShortDescription: sarifMessage{Text: "The example is invalid or rejected by Kubernetes " + run.KubernetesVersion}},
+					{ID: ruleWarning, ShortDescription: sarifMessage{Text: "The example raises a warning with Kubernetes " + run.KubernetesVersion}},
+				},
+			}},
+			Results: []sarifResult{},
+		}
+		for _, f := range run.Files {
+			for _, p := range f.Errors {
+				sr.Results = append(sr.Results, sarifProblem(f.Path, ruleError, "error", p, p.Message))
+			}
+			for _, d := range f.Documents {
+				for _, p := range d.Errors {
+					sr.Results = append(sr.Results, sarifProblem(f.Path, ruleError, "error", p, documentMessage(d, p)))
+				}
+				for _, p := range d.Warnings {
+					if !p.Expected {
+						sr.Results = append(sr.Results, sarifProblem(f.Path, ruleWarning, "warning", p, documentMessage(d, p)))
+					}
+				}
+			}
+		}
+		log.Runs = append(log.Runs, sr)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func documentMessage(d Document, p Problem) string {
+	return fmt.Sprintf("document %d (%s): %s", d.Index, d.Kind, p.Message)
+}
+
+func sarifProblem(path, rule, level string, p Problem, message string) sarifResult {
+	location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(path)}}
+	if p.Line > 0 {
+		location.Region = &sarifRegion{StartLine: p.Line, StartColumn: p.Column}
+	}
+	return sarifResult{
+		RuleID:    rule,
+		Level:     level,
+		Message:   sarifMessage{Text: message},
+		Locations: []sarifLocation{{PhysicalLocation: location}},
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"context"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/registry/customresourcedefinition"
+	"k8s.io/apimachinery/pkg/api/meta"
+	genericvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+	"k8s.io/kubernetes/pkg/registry/admissionregistration/mutatingwebhookconfiguration"
+	"k8s.io/kubernetes/pkg/registry/admissionregistration/validatingadmissionpolicy"
+	"k8s.io/kubernetes/pkg/registry/admissionregistration/validatingadmissionpolicybinding"
+	"k8s.io/kubernetes/pkg/registry/admissionregistration/validatingwebhookconfiguration"
+
+	"k8s.io/kubernetes/pkg/apis/apiserverinternal"
+	"k8s.io/kubernetes/pkg/registry/apiserverinternal/storageversion"
+
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/registry/apps/controllerrevision"
+	"k8s.io/kubernetes/pkg/registry/apps/daemonset"
+	"k8s.io/kubernetes/pkg/registry/apps/deployment"
+	"k8s.io/kubernetes/pkg/registry/apps/replicaset"
+	"k8s.io/kubernetes/pkg/registry/apps/statefulset"
+
+	"k8s.io/kubernetes/pkg/apis/autoscaling"
+	"k8s.io/kubernetes/pkg/registry/autoscaling/horizontalpodautoscaler"
+
+	"k8s.io/kubernetes/pkg/apis/batch"
+	"k8s.io/kubernetes/pkg/registry/batch/cronjob"
+	"k8s.io/kubernetes/pkg/registry/batch/job"
+
+	"k8s.io/kubernetes/pkg/apis/certificates"
+	certificatesigningrequest "k8s.io/kubernetes/pkg/registry/certificates/certificates"
+	"k8s.io/kubernetes/pkg/registry/certificates/clustertrustbundle"
+
+	"k8s.io/kubernetes/pkg/apis/coordination"
+	"k8s.io/kubernetes/pkg/registry/coordination/lease"
+
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/registry/core/configmap"
+	"k8s.io/kubernetes/pkg/registry/core/endpoint"
+	"k8s.io/kubernetes/pkg/registry/core/event"
+	"k8s.io/kubernetes/pkg/registry/core/limitrange"
+	"k8s.io/kubernetes/pkg/registry/core/namespace"
+	corenode "k8s.io/kubernetes/pkg/registry/core/node"
+	"k8s.io/kubernetes/pkg/registry/core/persistentvolume"
+	"k8s.io/kubernetes/pkg/registry/core/persistentvolumeclaim"
+	"k8s.io/kubernetes/pkg/registry/core/pod"
+	"k8s.io/kubernetes/pkg/registry/core/podtemplate"
+	"k8s.io/kubernetes/pkg/registry/core/replicationcontroller"
+	"k8s.io/kubernetes/pkg/registry/core/resourcequota"
+	"k8s.io/kubernetes/pkg/registry/core/secret"
+	"k8s.io/kubernetes/pkg/registry/core/service"
+	"k8s.io/kubernetes/pkg/registry/core/serviceaccount"
+
+	"k8s.io/kubernetes/pkg/apis/discovery"
+	"k8s.io/kubernetes/pkg/registry/discovery/endpointslice"
+
+	"k8s.io/kubernetes/pkg/apis/events"
+
+	"k8s.io/kubernetes/pkg/apis/flowcontrol"
+	"k8s.io/kubernetes/pkg/registry/flowcontrol/flowschema"
+	"k8s.io/kubernetes/pkg/registry/flowcontrol/prioritylevelconfiguration"
+
+	"k8s.io/kubernetes/pkg/apis/networking"
+	"k8s.io/kubernetes/pkg/registry/networking/ingress"
+	"k8s.io/kubernetes/pkg/registry/networking/ingressclass"
+	"k8s.io/kubernetes/pkg/registry/networking/ipaddress"
+	"k8s.io/kubernetes/pkg/registry/networking/networkpolicy"
+	"k8s.io/kubernetes/pkg/registry/networking/servicecidr"
+
+	"k8s.io/kubernetes/pkg/apis/node"
+	"k8s.io/kubernetes/pkg/registry/node/runtimeclass"
+
+	"k8s.io/kubernetes/pkg/apis/policy"
+	"k8s.io/kubernetes/pkg/registry/policy/poddisruptionbudget"
+
+	"k8s.io/kubernetes/pkg/apis/rbac"
+	"k8s.io/kubernetes/pkg/registry/rbac/clusterrole"
+	"k8s.io/kubernetes/pkg/registry/rbac/clusterrolebinding"
+	"k8s.io/kubernetes/pkg/registry/rbac/role"
+	"k8s.io/kubernetes/pkg/registry/rbac/rolebinding"
+
+	"k8s.io/kubernetes/pkg/apis/resource"
+	"k8s.io/kubernetes/pkg/registry/resource/podschedulingcontext"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceclaim"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceclaimparameters"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceclaimtemplate"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceclass"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceclassparameters"
+	"k8s.io/kubernetes/pkg/registry/resource/resourceslice"
+
+	"k8s.io/kubernetes/pkg/apis/scheduling"
+	"k8s.io/kubernetes/pkg/registry/scheduling/priorityclass"
+
+	"k8s.io/kubernetes/pkg/apis/storage"
+	"k8s.io/kubernetes/pkg/registry/storage/csidriver"
+	"k8s.io/kubernetes/pkg/registry/storage/csinode"
+	"k8s.io/kubernetes/pkg/registry/storage/csistoragecapacity"
+	"k8s.io/kubernetes/pkg/registry/storage/storageclass"
+	"k8s.io/kubernetes/pkg/registry/storage/volumeattachment"
+	"k8s.io/kubernetes/pkg/registry/storage/volumeattributesclass"
+
+	"k8s.io/kubernetes/pkg/apis/storagemigration"
+	storageversionmigration "k8s.io/kubernetes/pkg/registry/storagemigration/storagemigration"
+)
+
+// strategies holds the registry strategy the API server creates the
+// resources of each kind with, keyed by group and kind. Supporting a new
+// kind of example takes adding its strategy here.
+var strategies = map[schema.GroupKind]rest.RESTCreateStrategy{
+	admissionregistration.Kind("MutatingWebhookConfiguration"): mutatingwebhookconfiguration.Strategy,
+	// Without an authorizer, the strategies of policies and bindings do
+	// not check that the creator may read their parameters.
+	admissionregistration.Kind("ValidatingAdmissionPolicy"):        validatingadmissionpolicy.NewStrategy(nil, nil),
+	admissionregistration.Kind("ValidatingAdmissionPolicyBinding"): validatingadmissionpolicybinding.NewStrategy(nil, nil, nil),
+	admissionregistration.Kind("ValidatingWebhookConfiguration"):   validatingwebhookconfiguration.Strategy,
+
+	apiextensions.Kind("CustomResourceDefinition"): customresourcedefinition.NewStrategy(legacyscheme.Scheme),
+
+	apiserverinternal.Kind("StorageVersion"): storageversion.Strategy,
+
+	apps.Kind("ControllerRevision"): controllerrevision.Strategy,
+	apps.Kind("DaemonSet"):          daemonset.Strategy,
+	apps.Kind("Deployment"):         deployment.Strategy,
+	apps.Kind("ReplicaSet"):         replicaset.Strategy,
+	apps.Kind("StatefulSet"):        statefulset.Strategy,
+
+	autoscaling.Kind("HorizontalPodAutoscaler"): horizontalpodautoscaler.Strategy,
+
+	batch.Kind("CronJob"): cronjob.Strategy,
+	batch.Kind("Job"):     job.Strategy,
+
+	certificates.Kind("CertificateSigningRequest"): certificatesigningrequest.Strategy,
+	certificates.Kind("ClusterTrustBundle"):        clustertrustbundle.Strategy,
+
+	coordination.Kind("Lease"): lease.Strategy,
+
+	api.Kind("ConfigMap"):             configmap.Strategy,
+	api.Kind("Endpoints"):             endpoint.Strategy,
+	api.Kind("Event"):                 event.Strategy,
+	api.Kind("LimitRange"):            limitrange.Strategy,
+	api.Kind("Namespace"):             namespace.Strategy,
+	api.Kind("Node"):                  corenode.Strategy,
+	api.Kind("PersistentVolume"):      persistentvolume.Strategy,
+	api.Kind("PersistentVolumeClaim"): persistentvolumeclaim.Strategy,
+	api.Kind("Pod"):                   pod.Strategy,
+	api.Kind("PodTemplate"):           podtemplate.Strategy,
+	api.Kind("ReplicationController"): replicationcontroller.Strategy,
+	api.Kind("ResourceQuota"):         resourcequota.Strategy,
+	api.Kind("Secret"):                secret.Strategy,
+	api.Kind("Service"):               service.Strategy,
+	api.Kind("ServiceAccount"):        serviceaccount.Strategy,
+
+	discovery.Kind("EndpointSlice"): endpointslice.Strategy,
+
+	// events.k8s.io serves the events of the core group.
+	events.Kind("Event"): event.Strategy,
+
+	flowcontrol.Kind("FlowSchema"):                 flowschema.Strategy,
+	flowcontrol.Kind("PriorityLevelConfiguration"): prioritylevelconfiguration.Strategy,
+
+	networking.Kind("Ingress"):       ingress.Strategy,
+	networking.Kind("IngressClass"):  ingressclass.Strategy,
+	networking.Kind("IPAddress"):     ipaddress.Strategy,
+	networking.Kind("NetworkPolicy"): networkpolicy.Strategy,
+	networking.Kind("ServiceCIDR"):   servicecidr.Strategy,
+
+	node.Kind("RuntimeClass"): runtimeclass.Strategy,
+
+	policy.Kind("PodDisruptionBudget"): poddisruptionbudget.Strategy,
+
+	rbac.Kind("ClusterRole"):        clusterrole.Strategy,
+	rbac.Kind("ClusterRoleBinding"): clusterrolebinding.Strategy,
+	rbac.Kind("Role"):               role.Strategy,
+	rbac.Kind("RoleBinding"):        rolebinding.Strategy,
+
+	resource.Kind("PodSchedulingContext"):    podschedulingcontext.Strategy,
+	resource.Kind("ResourceClaim"):           resourceclaim.Strategy,
+	resource.Kind("ResourceClaimParameters"): resourceclaimparameters.Strategy,
+	resource.Kind("ResourceClaimTemplate"):   resourceclaimtemplate.Strategy,
+	resource.Kind("ResourceClass"):           resourceclass.Strategy,
+	resource.Kind("ResourceClassParameters"): resourceclassparameters.Strategy,
+	resource.Kind("ResourceSlice"):           resourceslice.Strategy,
+
+	scheduling.Kind("PriorityClass"): priorityclass.Strategy,
+
+	storage.Kind("CSIDriver"):             csidriver.Strategy,
+	storage.Kind("CSINode"):               csinode.Strategy,
+	storage.Kind("CSIStorageCapacity"):    csistoragecapacity.Strategy,
+	storage.Kind("StorageClass"):          storageclass.Strategy,
+	storage.Kind("VolumeAttachment"):      volumeattachment.Strategy,
+	storage.Kind("VolumeAttributesClass"): volumeattributesclass.Strategy,
+
+	storagemigration.Kind("StorageVersionMigration"): storageversionmigration.Strategy,
+}
+
+// exampleUser is the user examples are created by.
+var exampleUser = &user.DefaultInfo{Name: "examples"}
+
+// validateCreate runs the create path of the API server on an internal
+// object, decoded and defaulted from a document of group version gv, with
+// strategy: it sets the namespace of the request, which defaults to
+// "default" as kubectl does, fills in the system fields and generated name,
+// prepares the object for creation, validates it and returns the warnings
+// the API server would return along with the validation errors. The object
+// is canonicalized when valid.
+func validateCreate(strategy rest.RESTCreateStrategy, obj runtime.Object, gv schema.GroupVersion) (field.ErrorList, []string) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath("metadata"), err)}, nil
+	}
+	requestNamespace := accessor.GetNamespace()
+	if requestNamespace == "" {
+		requestNamespace = metav1.NamespaceDefault
+	}
+	requestNamespace = rest.ExpectedNamespaceForScope(requestNamespace, strategy.NamespaceScoped())
+	if err := rest.EnsureObjectNamespaceMatchesRequestNamespace(requestNamespace, accessor); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("metadata", "namespace"), accessor.GetNamespace(), err.Error())}, nil
+	}
+	rest.FillObjectMetaSystemFields(accessor)
+	if len(accessor.GetGenerateName()) > 0 && len(accessor.GetName()) == 0 {
+		accessor.SetName(strategy.GenerateName(accessor.GetGenerateName()))
+	}
+
+	ctx := genericapirequest.WithNamespace(context.TODO(), requestNamespace)
+	ctx = genericapirequest.WithUser(ctx, exampleUser)
+	ctx = genericapirequest.WithRequestInfo(ctx, &genericapirequest.RequestInfo{
+		IsResourceRequest: true,
+		Verb:              "create",
+		APIGroup:          gv.Group,
+		APIVersion:        gv.Version,
+		Namespace:         requestNamespace,
+		Name:              accessor.GetName(),
+	})
+
+	strategy.PrepareForCreate(ctx, obj)
+	// the REST storage of services fills in the cluster IPs of a service
+	// that sets only its cluster IP before validating it
+	if svc, ok := obj.(*api.Service); ok && len(svc.Spec.ClusterIP) > 0 && len(svc.Spec.ClusterIPs) == 0 {
+		svc.Spec.ClusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	errors := strategy.Validate(ctx, obj)
+	if len(errors) > 0 {
+		return errors, nil
+	}
+	errors = genericvalidation.ValidateObjectMetaAccessor(accessor, strategy.NamespaceScoped(), path.ValidatePathSegmentName, field.NewPath("metadata"))
+	if len(errors) > 0 {
+		return errors, nil
+	}
+	warnings := strategy.WarningsOnCreate(ctx, obj)
+	strategy.Canonicalize(obj)
+	return nil, warnings
+}
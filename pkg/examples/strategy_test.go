@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCreate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		source        string
+		wantFields    []string
+		wantName      string
+		wantNamespace string
+	}{{
+		name:          "valid",
+		source:        testConfigMap,
+		wantName:      "config",
+		wantNamespace: metav1.NamespaceDefault,
+	}, {
+		name: "invalid",
+		source: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers: []
+`,
+		wantFields: []string{"spec.containers"},
+	}, {
+		name: "generated name",
+		source: `apiVersion: v1
+kind: Pod
+metadata:
+  generateName: web-
+spec:
+  containers:
+  - name: web
+    image: nginx
+`,
+		wantName:      "web-",
+		wantNamespace: metav1.NamespaceDefault,
+	}, {
+		name: "namespace of a cluster-scoped object",
+		source: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+  namespace: default
+`,
+		// the API server clears the namespace of cluster-scoped objects
+		wantName: "web",
+	}, {
+		name: "headless service",
+		source: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  clusterIP: None
+  selector:
+    app: web
+  ports:
+  - port: 80
+`,
+		wantName:      "web",
+		wantNamespace: metav1.NamespaceDefault,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, gvk := decodeTestObject(t, tc.source)
+			strategy, ok := strategies[gvk.GroupKind()]
+			if !ok {
+				t.Fatalf("no strategy for %s", gvk.GroupKind())
+			}
+
+			errs, _ := validateCreate(strategy, obj, gvk.GroupVersion())
+			if fields := errorFields(errs); !slices.Equal(fields, tc.wantFields) {
+				t.Errorf("expected errors for %v, got %v", tc.wantFields, errs)
+			}
+			if tc.wantName == "" {
+				return
+			}
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name := accessor.GetName(); !strings.HasPrefix(name, tc.wantName) {
+				t.Errorf("expected a name starting with %q, got %q", tc.wantName, name)
+			}
+			if namespace := accessor.GetNamespace(); namespace != tc.wantNamespace {
+				t.Errorf("expected namespace %q, got %q", tc.wantNamespace, namespace)
+			}
+			if accessor.GetUID() == "" {
+				t.Error("expected the system fields to be filled in")
+			}
+		})
+	}
+}
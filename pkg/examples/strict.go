@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	schemaobjectmeta "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/objectmeta"
+	structuralpruning "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+)
+
+// strictCodecs decode documents the way the API server does for requests
+// with fieldValidation=Strict.
+var strictCodecs = serializer.NewCodecFactory(legacyscheme.Scheme, serializer.EnableStrict)
+
+// strictDecodingErrors returns the unknown and duplicate fields of a JSON
+// document declaring gvk, whose YAML source, if any, is source. The unknown
+// fields of a custom resource are those the API server would prune against
+// the schema of crd, the definition of its kind, if any.
+func strictDecodingErrors(data, source []byte, gvk schema.GroupVersionKind, crd *apiextensions.CustomResourceDefinition) []error {
+	var errs []error
+	// Duplicate keys of a YAML mapping are lost converting it to JSON.
+	for _, path := range duplicateYAMLFields(source) {
+		errs = append(errs, fmt.Errorf("strict decoding error: duplicate field %q", path))
+	}
+	if crd != nil {
+		for _, path := range unknownCustomResourceFields(data, gvk, crd) {
+			errs = append(errs, fmt.Errorf("strict decoding error: unknown field %q", path))
+		}
+		return errs
+	}
+
+	var into runtime.Object
+	if !legacyscheme.Scheme.Recognizes(gvk) {
+		into = &unstructured.Unstructured{}
+	}
+	_, _, err := strictCodecs.UniversalDeserializer().Decode(data, nil, into)
+	if strictErr, ok := runtime.AsStrictDecodingError(err); ok {
+		for _, err := range strictErr.Errors() {
+			errs = append(errs, fmt.Errorf("strict decoding error: %v", err))
+		}
+	}
+	// any other error is reported decoding the document
+	return errs
+}
+
+// unknownCustomResourceFields returns the path of every field of a custom
+// resource held by a JSON document that is neither part of its metadata nor
+// of the schema crd defines for its version, which the API server prunes,
+// or reports with fieldValidation=Strict.
+func unknownCustomResourceFields(data []byte, gvk schema.GroupVersionKind, crd *apiextensions.CustomResourceDefinition) []string {
+	if crd.Spec.PreserveUnknownFields != nil && *crd.Spec.PreserveUnknownFields {
+		return nil
+	}
+	crv, err := apiextensions.GetSchemaForVersion(crd, gvk.Version)
+	if err != nil || crv == nil || crv.OpenAPIV3Schema == nil {
+		return nil
+	}
+	structural, err := structuralschema.NewStructural(crv.OpenAPIV3Schema)
+	if err != nil {
+		// reported validating the resource
+		return nil
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	_, _, paths, err := schemaobjectmeta.GetObjectMetaWithOptions(obj, schemaobjectmeta.ObjectMetaOptions{ReturnUnknownFieldPaths: true})
+	if err != nil {
+		return nil
+	}
+	return append(paths, structuralpruning.PruneWithOptions(obj, structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})...)
+}
+
+// duplicateYAMLFields returns the path of every key repeated in a mapping of
+// a YAML document, such as spec.template.spec.containers[0].image.
+func duplicateYAMLFields(source []byte) []string {
+	if source == nil {
+		return nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(source, &node); err != nil {
+		return nil
+	}
+	var paths []string
+	var walk func(n *yaml.Node, path string)
+	walk = func(n *yaml.Node, path string) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(c, path)
+			}
+		case yaml.SequenceNode:
+			for i, c := range n.Content {
+				walk(c, path+"["+strconv.Itoa(i)+"]")
+			}
+		case yaml.MappingNode:
+			seen := map[string]bool{}
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				child := key.Value
+				if path != "" {
+					child = path + "." + key.Value
+				}
+				if seen[key.Value] {
+					paths = append(paths, child)
+				}
+				seen[key.Value] = true
+				walk(value, child)
+			}
+		}
+	}
+	walk(&node, "")
+	return paths
+}
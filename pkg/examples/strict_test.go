@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+)
+
+func TestStrictDecodingErrors(t *testing.T) {
+	data, _ := decodeTestDocument(t, crontabDefinition)
+	crd := decodeCustomResourceDefinition(data)
+	if crd == nil {
+		t.Fatal("the CustomResourceDefinition was not decoded")
+	}
+
+	for _, tc := range []struct {
+		name       string
+		source     string
+		wantErrors []string
+	}{{
+		name:   "valid",
+		source: testConfigMap,
+	}, {
+		name: "unknown field",
+		source: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  label:
+    app: web
+`,
+		wantErrors: []string{`unknown field "metadata.label"`},
+	}, {
+		name: "duplicate field",
+		source: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: nginx
+    image: httpd
+`,
+		wantErrors: []string{`duplicate field "spec.containers[0].image"`},
+	}, {
+		name: "valid custom resource",
+		source: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  cronSpec: "* * * * */5"
+`,
+	}, {
+		name: "unknown field of a custom resource",
+		source: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  cronSpc: "* * * * */5"
+`,
+		wantErrors: []string{`unknown field "spec.cronSpc"`},
+	}, {
+		name: "duplicate field of a custom resource",
+		source: `apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: crontab
+spec:
+  replicas: 1
+  replicas: 2
+`,
+		wantErrors: []string{`duplicate field "spec.replicas"`},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, gvk := decodeTestDocument(t, tc.source)
+			var definition *apiextensions.CustomResourceDefinition
+			if gvk.Group == crd.Spec.Group {
+				definition = crd
+			}
+			errs := strictDecodingErrors(data, []byte(tc.source), gvk, definition)
+			if len(errs) != len(tc.wantErrors) {
+				t.Fatalf("expected errors for %v, got %v", tc.wantErrors, errs)
+			}
+			for i, want := range tc.wantErrors {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("expected %q in %q", want, errs[i])
+				}
+			}
+		})
+	}
+}
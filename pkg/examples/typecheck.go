@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission/plugin/policy/validating"
+	"k8s.io/apiserver/pkg/cel/openapi/resolver"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+)
+
+// typeChecker returns the type checker of the CEL expressions of policies.
+// It resolves the schemas of built-in kinds from the OpenAPI definitions of
+// Kubernetes, and those of custom resources from their definitions.
+func (c *Checker) typeChecker() *validating.TypeChecker {
+	c.typeCheckerOnce.Do(c.newTypeChecker)
+	return c.policyTypeChecker
+}
+
+func (c *Checker) newTypeChecker() {
+	crds := c.customResourceDefinitions()
+
+	// The scheme does not tell the scope of built-in resources, take them
+	// all as namespaced as most of them are. The scope only matters to
+	// admission scenarios, whose requests default to the "default"
+	// namespace.
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, scheme := range []*runtime.Scheme{legacyscheme.Scheme, clientgoscheme.Scheme} {
+		for gvk := range scheme.AllKnownTypes() {
+			if gvk.Version != runtime.APIVersionInternal {
+				mapper.Add(gvk, meta.RESTScopeNamespace)
+			}
+		}
+	}
+	for _, crd := range crds {
+		scope := meta.RESTScopeRoot
+		if crd.Spec.Scope == apiextensions.NamespaceScoped {
+			scope = meta.RESTScopeNamespace
+		}
+		for _, v := range crd.Spec.Versions {
+			gv := schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}
+			mapper.AddSpecific(gv.WithKind(crd.Spec.Names.Kind), gv.WithResource(crd.Spec.Names.Plural), gv.WithResource(crd.Spec.Names.Singular), scope)
+		}
+	}
+
+	c.policyTypeChecker = &validating.TypeChecker{
+		SchemaResolver: c.builtinSchemas().Combine(customResourceSchemaResolver(crds)),
+		RestMapper:     mapper,
+	}
+}
+
+// typeCheckPolicy type checks every CEL expression of a policy against the
+// schemas of the resources it matches and of its parameters, and returns the
+// warnings the API server would set in the status of the policy.
+func (c *Checker) typeCheckPolicy(obj *admissionregistration.ValidatingAdmissionPolicy) ([]Warning, error) {
+	policy := &admissionregistrationv1.ValidatingAdmissionPolicy{}
+	if err := legacyscheme.Scheme.Convert(obj, policy, nil); err != nil {
+		return nil, err
+	}
+	checker := c.typeChecker()
+	var warnings []Warning
+	// validations and their message expressions
+	for _, w := range checker.Check(policy) {
+		warnings = append(warnings, Warning{FieldRef: w.FieldRef, Message: strings.TrimSpace(w.Warning)})
+	}
+
+	ctx := checker.CreateContext(policy)
+	check := func(fldPath *field.Path, expression string) {
+		if results := checker.CheckExpression(ctx, expression); len(results) != 0 {
+			warnings = append(warnings, Warning{FieldRef: fldPath.String(), Message: strings.TrimSpace(results.String())})
+		}
+	}
+	for i, v := range policy.Spec.Variables {
+		check(field.NewPath("spec", "variables").Index(i).Child("expression"), v.Expression)
+	}
+	for i, mc := range policy.Spec.MatchConditions {
+		check(field.NewPath("spec", "matchConditions").Index(i).Child("expression"), mc.Expression)
+	}
+	for i, aa := range policy.Spec.AuditAnnotations {
+		check(field.NewPath("spec", "auditAnnotations").Index(i).Child("valueExpression"), aa.ValueExpression)
+	}
+	return warnings, nil
+}
+
+// customResourceSchemaResolver resolves the schemas of custom resources from
+// their definitions, keyed by group and kind.
+type customResourceSchemaResolver map[schema.GroupKind]*apiextensions.CustomResourceDefinition
+
+func (r customResourceSchemaResolver) ResolveSchema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	crd, ok := r[gvk.GroupKind()]
+	if !ok || !apiextensions.HasServedCRDVersion(crd, gvk.Version) {
+		return nil, fmt.Errorf("cannot resolve %v: %w", gvk, resolver.ErrSchemaNotFound)
+	}
+	crv, err := apiextensions.GetSchemaForVersion(crd, gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if crv == nil || crv.OpenAPIV3Schema == nil {
+		return nil, fmt.Errorf("cannot resolve %v: %w", gvk, resolver.ErrSchemaNotFound)
+	}
+	structural, err := structuralschema.NewStructural(crv.OpenAPIV3Schema)
+	if err != nil {
+		return nil, err
+	}
+	s := structural.ToKubeOpenAPI()
+	// apiVersion and kind are implicit in the schema of a custom resource
+	if s.Properties == nil {
+		s.Properties = map[string]spec.Schema{}
+	}
+	s.Properties["apiVersion"] = *spec.StringProperty()
+	s.Properties["kind"] = *spec.StringProperty()
+	return s, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/apis/admissionregistration"
+)
+
+// replicaLimitPolicy is a policy matching Deployments, formatted with the
+// expressions of its variable and validation.
+const replicaLimitPolicy = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: replica-limit
+spec:
+  failurePolicy: Fail
+  matchConstraints:
+    resourceRules:
+    - apiGroups: ["apps"]
+      apiVersions: ["v1"]
+      operations: ["CREATE", "UPDATE"]
+      resources: ["deployments"]
+  variables:
+  - name: replicas
+    expression: %q
+  validations:
+  - expression: %q
+`
+
+func TestTypeCheckPolicy(t *testing.T) {
+	kubeVersion, err := TargetVersion("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := NewChecker(t.TempDir(), kubeVersion)
+
+	for _, tc := range []struct {
+		name         string
+		variable     string
+		validation   string
+		wantFieldRef []string
+	}{{
+		name:       "valid",
+		variable:   "object.spec.replicas",
+		validation: "variables.replicas <= 5",
+	}, {
+		name:         "unknown field in validation",
+		variable:     "object.spec.replicas",
+		validation:   "object.spec.replicaCount <= 5",
+		wantFieldRef: []string{"spec.validations[0].expression"},
+	}, {
+		name:         "unknown field in variable",
+		variable:     "object.spec.replicaCount",
+		validation:   "true",
+		wantFieldRef: []string{"spec.variables[0].expression"},
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, _ := decodeTestObject(t, fmt.Sprintf(replicaLimitPolicy, tc.variable, tc.validation))
+			warnings, err := checker.typeCheckPolicy(obj.(*admissionregistration.ValidatingAdmissionPolicy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) != len(tc.wantFieldRef) {
+				t.Fatalf("expected warnings for %v, got %+v", tc.wantFieldRef, warnings)
+			}
+			for i, fieldRef := range tc.wantFieldRef {
+				if warnings[i].FieldRef != fieldRef {
+					t.Errorf("expected a warning for %s, got %+v", fieldRef, warnings[i])
+				}
+			}
+		})
+	}
+}
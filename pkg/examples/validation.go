@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/apis/audit"
+	audit_validation "k8s.io/apiserver/pkg/apis/audit/validation"
+	"k8s.io/apiserver/pkg/server/egressselector"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+	"k8s.io/kubernetes/pkg/apis/authorization"
+	authorization_validation "k8s.io/kubernetes/pkg/apis/authorization/validation"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	schedulerconfig_validation "k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+)
+
+// documentKind reads the apiVersion and kind of a JSON document.
+func documentKind(data []byte) (schema.GroupVersionKind, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("unable to read apiVersion and kind: %v", err)
+	}
+	if typeMeta.APIVersion == "" || typeMeta.Kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("apiVersion and kind must be set")
+	}
+	return schema.FromAPIVersionAndKind(typeMeta.APIVersion, typeMeta.Kind), nil
+}
+
+// newObjectForKind returns an empty internal object of the type registered
+// in the scheme for gvk.
+func newObjectForKind(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	if !legacyscheme.Scheme.Recognizes(gvk) {
+		return nil, fmt.Errorf("unknown apiVersion/kind: %s", gvk)
+	}
+	return legacyscheme.Scheme.New(gvk.GroupKind().WithVersion(runtime.APIVersionInternal))
+}
+
+// decodeObject decodes a JSON document in the apiVersion it declares, gvk,
+// defaults it and converts it into a new internal object.
+func decodeObject(data []byte, gvk schema.GroupVersionKind) (runtime.Object, error) {
+	obj, err := newObjectForKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	external, err := legacyscheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DecodeInto(legacyscheme.Codecs.UniversalDeserializer(), data, external); err != nil {
+		return nil, fmt.Errorf("did not decode correctly: %v", err)
+	}
+	legacyscheme.Scheme.Default(external)
+	if err := legacyscheme.Scheme.Convert(external, obj, nil); err != nil {
+		return nil, fmt.Errorf("unable to convert %s to its internal version: %v", gvk, err)
+	}
+	return obj, nil
+}
+
+// validators validate the kinds the API server has no registry strategy
+// for, keyed by group and kind: the reviews the API server answers without
+// persisting them, and the configuration files of the control plane
+// components.
+var validators = map[schema.GroupKind]func(runtime.Object) field.ErrorList{
+	authorization.Kind("LocalSubjectAccessReview"): func(obj runtime.Object) field.ErrorList {
+		return authorization_validation.ValidateLocalSubjectAccessReview(obj.(*authorization.LocalSubjectAccessReview))
+	},
+	authorization.Kind("SelfSubjectAccessReview"): func(obj runtime.Object) field.ErrorList {
+		return authorization_validation.ValidateSelfSubjectAccessReview(obj.(*authorization.SelfSubjectAccessReview))
+	},
+	authorization.Kind("SubjectAccessReview"): func(obj runtime.Object) field.ErrorList {
+		return authorization_validation.ValidateSubjectAccessReview(obj.(*authorization.SubjectAccessReview))
+	},
+	// EgressSelectorConfiguration is served by both groups of the
+	// configuration of the API server, apiserver.k8s.io up to v1beta1.
+	{Group: apiserver.LegacyGroupName, Kind: "EgressSelectorConfiguration"}: validateEgressSelectorConfiguration,
+	{Group: apiserver.GroupName, Kind: "EgressSelectorConfiguration"}:       validateEgressSelectorConfiguration,
+	{Group: audit.GroupName, Kind: "Policy"}: func(obj runtime.Object) field.ErrorList {
+		return audit_validation.ValidatePolicy(obj.(*audit.Policy))
+	},
+	{Group: schedulerconfig.GroupName, Kind: "KubeSchedulerConfiguration"}: func(obj runtime.Object) field.ErrorList {
+		return aggregateToErrorList(schedulerconfig_validation.ValidateKubeSchedulerConfiguration(obj.(*schedulerconfig.KubeSchedulerConfiguration)))
+	},
+}
+
+// validateEgressSelectorConfiguration validates an EgressSelectorConfiguration
+// the way the API server does when it loads it.
+func validateEgressSelectorConfiguration(obj runtime.Object) field.ErrorList {
+	return egressselector.ValidateEgressSelectorConfiguration(obj.(*apiserver.EgressSelectorConfiguration))
+}
+
+// hasValidation reports whether ValidateObject validates the documents of
+// kind gvk.
+func hasValidation(gvk schema.GroupVersionKind) bool {
+	obj, err := newObjectForKind(gvk)
+	if err != nil {
+		return false
+	}
+	if meta.IsListType(obj) {
+		return true
+	}
+	_, hasValidator := validators[gvk.GroupKind()]
+	_, hasStrategy := strategies[gvk.GroupKind()]
+	return hasValidator || hasStrategy
+}
+
+// ValidateObject validates an internal object, decoded and defaulted from a
+// document of group version gv, the way the API server validates it on
+// creation, and returns the warnings the API server would return for it. The
+// items of a list are validated in turn. Kinds without a registry strategy
+// are validated by their validator, such as the configuration files of the
+// control plane components, which are validated the way the components
+// validate them when they load them, and so are those a ConfigMap holds.
+func ValidateObject(obj runtime.Object, gv schema.GroupVersion) (errors field.ErrorList, warnings []string) {
+	if meta.IsListType(obj) {
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return field.ErrorList{field.InternalError(field.NewPath("items"), err)}, nil
+		}
+		for i, item := range items {
+			itemErrors, itemWarnings := ValidateObject(item, gv)
+			for _, err := range itemErrors {
+				errors = append(errors, prefixFieldError(field.NewPath("items").Index(i), err))
+			}
+			warnings = append(warnings, itemWarnings...)
+		}
+		return errors, warnings
+	}
+
+	kinds, _, err := legacyscheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(field.NewPath(""), err)}, nil
+	}
+	if validate, ok := validators[kinds[0].GroupKind()]; ok {
+		return validate(obj), nil
+	}
+	strategy, ok := strategies[kinds[0].GroupKind()]
+	if !ok {
+		return field.ErrorList{field.InternalError(field.NewPath(""), fmt.Errorf("no validation defined for %s", kinds[0].GroupKind()))}, nil
+	}
+	errors, warnings = validateCreate(strategy, obj, gv)
+	if cm, ok := obj.(*api.ConfigMap); ok {
+		configErrors, configWarnings := validateEmbeddedConfigs(cm)
+		errors = append(errors, configErrors...)
+		warnings = append(warnings, configWarnings...)
+	}
+	return errors, warnings
+}
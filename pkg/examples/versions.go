@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+)
+
+// The lifecycle of the versions of built-in APIs is generated by
+// prerelease-lifecycle-gen on their external types.
+type apiLifecycleIntroduced interface {
+	APILifecycleIntroduced() (major, minor int)
+}
+
+type apiLifecycleRemoved interface {
+	APILifecycleRemoved() (major, minor int)
+}
+
+// newExternalObject returns an empty object of the external type registered
+// for gvk, in the scheme the API server serves it from.
+func newExternalObject(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	obj, err := legacyscheme.Scheme.New(gvk)
+	if err != nil {
+		if obj, err = clientgoscheme.Scheme.New(gvk); err != nil {
+			return nil, err
+		}
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return obj, nil
+}
+
+// notServedError returns an error when Kubernetes release v does not serve
+// the built-in kind gvk in its version, because the version was removed
+// before v or introduced after it.
+func notServedError(gvk schema.GroupVersionKind, v *version.Version) error {
+	obj, err := newExternalObject(gvk)
+	if err != nil {
+		return nil
+	}
+	if removed, ok := obj.(apiLifecycleRemoved); ok {
+		major, minor := removed.APILifecycleRemoved()
+		if (major != 0 || minor != 0) && !v.LessThan(version.MajorMinor(uint(major), uint(minor))) {
+			return fmt.Errorf("%s %s is not served by Kubernetes %s, it was removed in v%d.%d", gvk.GroupVersion(), gvk.Kind, v, major, minor)
+		}
+	}
+	if introduced, ok := obj.(apiLifecycleIntroduced); ok {
+		major, minor := introduced.APILifecycleIntroduced()
+		if (major != 0 || minor != 0) && v.LessThan(version.MajorMinor(uint(major), uint(minor))) {
+			return fmt.Errorf("%s %s is not served by Kubernetes %s, it was introduced in v%d.%d", gvk.GroupVersion(), gvk.Kind, v, major, minor)
+		}
+	}
+	return nil
+}
+
+// servedVersions returns the versions of group kind gk Kubernetes release v
+// serves, from the preferred one.
+func servedVersions(gk schema.GroupKind, v *version.Version) []schema.GroupVersion {
+	var served []schema.GroupVersion
+	for _, gv := range legacyscheme.Scheme.PrioritizedVersionsForGroup(gk.Group) {
+		gvk := gv.WithKind(gk.Kind)
+		if legacyscheme.Scheme.Recognizes(gvk) && notServedError(gvk, v) == nil {
+			served = append(served, gv)
+		}
+	}
+	return served
+}
+
+// roundTripWarnings converts an internal object of group kind gk to every
+// version Kubernetes release v serves and back, and returns a warning for
+// each conversion that loses part of the object, as reading it back in that
+// version would.
+func roundTripWarnings(obj runtime.Object, gk schema.GroupKind, v *version.Version) []Warning {
+	if meta.IsListType(obj) {
+		return nil
+	}
+	original := obj.DeepCopyObject()
+	original.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+
+	var warnings []Warning
+	for _, gv := range servedVersions(gk, v) {
+		external, err := legacyscheme.Scheme.ConvertToVersion(original.DeepCopyObject(), gv)
+		if err != nil {
+			warnings = append(warnings, Warning{Message: fmt.Sprintf("unable to convert to %s: %v", gv, err)})
+			continue
+		}
+		converted, err := newObjectForKind(gv.WithKind(gk.Kind))
+		if err != nil {
+			continue
+		}
+		if err := legacyscheme.Scheme.Convert(external, converted, nil); err != nil {
+			warnings = append(warnings, Warning{Message: fmt.Sprintf("unable to convert from %s: %v", gv, err)})
+			continue
+		}
+		converted.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		if !apiequality.Semantic.DeepEqual(original, converted) {
+			warnings = append(warnings, Warning{Message: fmt.Sprintf("converting to %s and back is lossy (-original +converted):\n%s", gv, cmp.Diff(original, converted))})
+		}
+	}
+	return warnings
+}
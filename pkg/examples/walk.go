@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package examples
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// WalkConfigFiles walks inDir and all of its subdirectories for any json/yaml
+// files, and calls fn with the path of each file found. Manifest files and
+// the golden files of GoldenDir directories are not examples and are left
+// out.
+func WalkConfigFiles(inDir string, fn func(path string) error) error {
+	return filepath.Walk(inDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == GoldenDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		file := filepath.Base(path)
+		if file == ManifestFile {
+			return nil
+		}
+		if ext := filepath.Ext(file); ext == ".json" || ext == ".yaml" {
+			return fn(path)
+		}
+		return nil
+	})
+}
+
+// IsExamplesDir reports whether dir is the examples directory of a locale,
+// content/<locale>/examples, whose files get the expectations of the
+// ManifestFile of their directory.
+func IsExamplesDir(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return filepath.Base(abs) == "examples" && filepath.Base(filepath.Dir(filepath.Dir(abs))) == "content"
+}
+
+// ReadConfigFile reads a json/yaml file. Converts yaml to json, and returns
+// the contents of each document in the file.
+func ReadConfigFile(path string) ([][]byte, error) {
+	docs, _, _, err := readConfigFile(path)
+	return docs, err
+}
+
+// readConfigFile reads a json/yaml file like ReadConfigFile, and returns the
+// YAML source of each document as well, and the line of the file it starts
+// at. Sources are nil for a json file.
+func readConfigFile(path string) (docs, sources [][]byte, lines []int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return splitConfigData(path, data)
+}
+
+// splitConfigData splits data, the content of the file at path, like
+// readConfigFile. Files without a .json extension are read as YAML, which
+// JSON is a subset of.
+func splitConfigData(path string, data []byte) (docs, sources [][]byte, lines []int, err error) {
+	if filepath.Ext(path) == ".json" {
+		return [][]byte{data}, [][]byte{nil}, []int{1}, nil
+	}
+	return splitYAML(data)
+}
+
+// splitYAMLDocuments converts the documents of a YAML stream to JSON, leaving
+// out empty ones.
+func splitYAMLDocuments(data []byte) ([][]byte, error) {
+	docs, _, _, err := splitYAML(data)
+	return docs, err
+}
+
+// splitYAML converts the documents of a YAML stream to JSON, leaving out
+// empty ones, and returns the YAML source of each along with it, and the
+// line of the stream it starts at.
+func splitYAML(data []byte) (docs, sources [][]byte, lines []int, err error) {
+	starts := documentStartLines(data)
+	// YAML can contain multiple documents.
+	splitter := yaml.NewYAMLReader(bufio.NewReader(bytes.NewBuffer(data)))
+	for i := 0; ; i++ {
+		doc, err := splitter.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		out, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// deal with "empty" document (e.g. pure comments)
+		if string(out) != "null" {
+			docs = append(docs, out)
+			sources = append(sources, doc)
+			line := 1
+			if i < len(starts) {
+				line = starts[i]
+			}
+			lines = append(lines, line)
+		}
+	}
+	return docs, sources, lines, nil
+}
+
+// Locales returns the examples directory of every locale under contentDir.
+func Locales(contentDir string) ([]string, error) {
+	dirs, err := filepath.Glob(filepath.Join(contentDir, "*", "examples"))
+	if err != nil {
+		return nil, err
+	}
+	var locales []string
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			locales = append(locales, dir)
+		}
+	}
+	return locales, nil
+}
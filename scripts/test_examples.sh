@@ -1,14 +1,19 @@
 #!/bin/bash
 
+# Validates the example files of every locale changed since a base revision,
+# along with the files referring to them.
+#
+# Usage: test_examples.sh install|run [base]
+#
+# The base revision defaults to the point the branch forked from master.
+
 set -e
 
-# List files changed in the commit to check
-FILES=($( git diff "$( git merge-base --fork-point master )" --name-only ))
+BASE=${2:-$( git merge-base --fork-point master || git merge-base master HEAD )}
 
+# Check if examples folders (all locales) change since the base revision
 TEST_EXAMPLES=No
-
-# Check if examples folders (all locales) change in this branch
-if printf -- '%s\n' "${FILES[@]}" | grep -qE '^"?content/[^/]+/examples/'; then
+if git diff "$BASE" --name-only | grep -qE '^"?content/[^/]+/examples/'; then
     TEST_EXAMPLES=Yes
 fi
 
@@ -33,9 +38,6 @@ function install() {
   cp -L -R vendor $GOPATH/src/
   rm -r vendor
   popd
-
-  # Fetch additional dependencies to run the tests in examples/examples_test.go
-  go get -t -v k8s.io/website/content/en/examples
 }
 
 function run_test() {
@@ -43,7 +45,8 @@ function run_test() {
     echo "PR not touching examples, skipping example tests execution" 1>&2
     exit 0
   fi
-  go test -v k8s.io/website/content/en/examples
+  # Only the changed example files and the files referring to them are checked
+  go test -v k8s.io/website/pkg/examples/examplestest -run TestLocales -args -changed-since="$BASE"
 }
 
 if [[ $1 == install ]]; then